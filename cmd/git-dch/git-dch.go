@@ -33,7 +33,6 @@
 package main
 
 import (
-	"log"
 	"os"
 
 	"github.com/cinello/git-dch/internal/app/git-dch"
@@ -41,9 +40,8 @@ import (
 
 func main() {
 	if err := git_dch.RunApplication(); err != nil {
-		log.SetOutput(os.Stderr)
-		log.Printf("ERROR: %s", err)
-		os.Exit(1)
+		git_dch.LogError(err)
+		os.Exit(git_dch.ExitCode(err))
 	}
 	os.Exit(0)
 }
@@ -1,6 +1,6 @@
 module github.com/cinello/git-dch
 
-go 1.12
+go 1.13
 
 require (
 	github.com/cinello/go-debian v0.0.0-20190308102310-19c5dc38a080
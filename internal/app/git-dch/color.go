@@ -0,0 +1,203 @@
+package git_dch
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cinello/git-dch/pkg/changelog"
+	"github.com/cinello/git-dch/pkg/logging"
+)
+
+var (
+	regExColorCode = regexp.MustCompile(`^\d+(;\d+)*$`)
+
+	// colorNames are the names accepted in --color-scheme, besides the SGR codes
+	colorNames = map[string]string{
+		"black":   "30",
+		"red":     "31",
+		"green":   "32",
+		"yellow":  "33",
+		"blue":    "34",
+		"magenta": "35",
+		"cyan":    "36",
+		"white":   "37",
+		"bold":    "1",
+	}
+
+	// colors is the scheme in use, colorStdout and colorStderr are true when
+	// the colors are enabled on the standard output and error
+	colors      = defaultColorScheme
+	colorStdout bool
+	colorStderr bool
+)
+
+// colorScheme holds the SGR parameters of the colored elements, as
+// --color-scheme=debug:info:warning:error:added:removed:hunk:version
+type colorScheme struct {
+	debug   string
+	info    string
+	warning string
+	error   string
+	added   string
+	removed string
+	hunk    string
+	version string
+}
+
+var defaultColorScheme = colorScheme{
+	debug:   "36",
+	info:    "32",
+	warning: "33",
+	error:   "31",
+	added:   "32",
+	removed: "31",
+	hunk:    "36",
+	version: "1",
+}
+
+// parseColorScheme returns the default scheme with the colors given in a
+// colon separated list replaced; an empty item keeps the default color
+func parseColorScheme(value string) (scheme colorScheme, err error) {
+	scheme = defaultColorScheme
+	if value == "" {
+		return
+	}
+
+	fields := []*string{
+		&scheme.debug, &scheme.info, &scheme.warning, &scheme.error,
+		&scheme.added, &scheme.removed, &scheme.hunk, &scheme.version,
+	}
+	values := strings.Split(value, ":")
+	if len(values) > len(fields) {
+		return scheme, fmt.Errorf("the color scheme %s has more than %d colors", value, len(fields))
+	}
+	for i, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		switch {
+		case v == "":
+		case colorNames[v] != "":
+			*fields[i] = colorNames[v]
+		case regExColorCode.MatchString(v):
+			*fields[i] = v
+		default:
+			return scheme, fmt.Errorf("the color %s in the color scheme %s is not valid", v, value)
+		}
+	}
+
+	return
+}
+
+// isTerminal returns true if the file is a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorEnabled returns true if the output to the file is colored: always with
+// --color=on, never with --color=off and, in auto mode, when the file is a
+// terminal and neither NO_COLOR is set nor TERM is dumb
+func colorEnabled(f *os.File) bool {
+	switch options.Color {
+	case "on":
+		return true
+	case "off":
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	return isTerminal(f)
+}
+
+// configureColors reads the color options, and colors the level names of the logger
+func configureColors() (err error) {
+	if colors, err = parseColorScheme(options.ColorScheme); err != nil {
+		return
+	}
+	colorStdout = colorEnabled(os.Stdout)
+	colorStderr = colorEnabled(os.Stderr)
+
+	if colorStderr {
+		logger.SetColors(map[logging.Level]string{
+			logging.LevelDebug:   colors.debug,
+			logging.LevelInfo:    colors.info,
+			logging.LevelWarning: colors.warning,
+			logging.LevelError:   colors.error,
+		})
+	}
+
+	return
+}
+
+// paint returns the text with the color, when enabled
+func paint(enabled bool, color, text string) string {
+	if !enabled || color == "" || text == "" {
+		return text
+	}
+
+	return "\x1b[" + color + "m" + text + "\x1b[0m"
+}
+
+// colorDiff returns a unified diff with the headers, the hunks and the added
+// and removed lines colored, when enabled
+func colorDiff(enabled bool, diff string) string {
+	if !enabled {
+		return diff
+	}
+
+	lines := strings.SplitAfter(diff, "\n")
+	for i, line := range lines {
+		text := strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(text, "--- "), strings.HasPrefix(text, "+++ "):
+			text = paint(true, "1", text)
+		case strings.HasPrefix(text, "@@"):
+			text = paint(true, colors.hunk, text)
+		case strings.HasPrefix(text, "+"):
+			text = paint(true, colors.added, text)
+		case strings.HasPrefix(text, "-"):
+			text = paint(true, colors.removed, text)
+		}
+		if strings.HasSuffix(line, "\n") {
+			text += "\n"
+		}
+		lines[i] = text
+	}
+
+	return strings.Join(lines, "")
+}
+
+// printDiff prints the changes made to a changelog: on the standard output
+// with --dry-run, on the standard error with --verbose
+func printDiff(name, before string, f *changelog.File) error {
+	if !options.DryRun && !options.Verbose {
+		return nil
+	}
+
+	var after strings.Builder
+	if _, err := f.Write(&after); err != nil {
+		return err
+	}
+	if name == standardStream {
+		name = standardChangelogFile
+	}
+	diff := changelog.Diff(path.Clean(filepath.ToSlash(name)), before, after.String(), 3)
+
+	if options.DryRun {
+		fmt.Fprint(os.Stdout, colorDiff(colorStdout, diff))
+	} else {
+		fmt.Fprint(os.Stderr, colorDiff(colorStderr, diff))
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package git_dch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cinello/git-dch/pkg/changelog"
+)
+
+// ExportCommand contains the options of the export command
+type ExportCommand struct {
+	Format        string `long:"format" description:"Output format" choice:"json" choice:"yaml" choice:"markdown" choice:"rpm" default:"json"`
+	SkipSnapshots bool   `long:"skip-snapshots" description:"Omit the snapshot and development entries"`
+
+	Args struct {
+		Filename string
+	} `positional-args:"yes"`
+}
+
+// runExport prints the entries of a changelog file in the requested format
+func runExport() (err error) {
+	filename := firstNotEmpty(options.Export.Args.Filename, standardChangelogFile)
+
+	var f *changelog.File
+	if f, err = changelog.NewFromFile(filepath.FromSlash(filename)); err != nil {
+		return exitCodeError{code: exitChangelog, err: fmt.Errorf("cannot open changelog file %s: %w", filename, err)}
+	}
+
+	entries := f.Export(options.Export.SkipSnapshots)
+	switch options.Export.Format {
+	case "yaml":
+		return changelog.ExportYAML(os.Stdout, entries)
+	case "markdown":
+		return changelog.ExportMarkdown(os.Stdout, entries)
+	case "rpm":
+		return changelog.ExportRPM(os.Stdout, entries)
+	}
+
+	return changelog.ExportJSON(os.Stdout, entries)
+}
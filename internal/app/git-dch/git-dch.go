@@ -1,13 +1,18 @@
 package git_dch
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 
 	"github.com/cinello/git-dch/pkg/changelog"
 	"github.com/cinello/git-dch/pkg/dchversion"
 	"github.com/cinello/git-dch/pkg/git"
+	"github.com/cinello/git-dch/pkg/logging"
 
 	"github.com/jessevdk/go-flags"
 )
@@ -16,6 +21,16 @@ import (
 
 const (
 	standardChangelogFile = "./debian/changelog"
+	// standardStream is the file name of the standard input or output
+	standardStream = "-"
+)
+
+var (
+	// standardConfigFiles are read, when present, from the highest to the lowest priority
+	standardConfigFiles = []string{
+		"./debian/git-dch.conf",
+		"~/.git-dch.conf",
+	}
 )
 
 var (
@@ -45,17 +60,31 @@ var (
 		"oneiric",
 		"precise",
 		"quantal",
-		"rairing",
+		"raring",
 		"saucy",
 		"trusty",
 		"utopic",
 		"vivid",
-		"willy",
+		"wily",
 		"xenial",
 		"yakkety",
 		"zesty",
 		"artful",
 		"bionic",
+		"cosmic",
+		"disco",
+		"eoan",
+		"focal",
+		"groovy",
+		"hirsute",
+		"impish",
+		"jammy",
+		"kinetic",
+		"lunar",
+		"mantic",
+		"noble",
+		"oracular",
+		"plucky",
 		// Debian
 		"stable",
 		"hamm",
@@ -70,46 +99,197 @@ var (
 		"jessie",
 		"stretch",
 		"buster",
+		"bullseye",
+		"bookworm",
+		"trixie",
+		"forky",
 		// Other
 		"unstable",
 	}
+	// dStableSuffixes are the suites derived from a stable distribution,
+	// e.g. bookworm-security; a suffix ending with another one comes first
+	dStableSuffixes = []string{
+		"-security",
+		"-proposed-updates",
+		"-updates",
+		"-proposed",
+		"-backports-sloppy",
+		"-backports",
+	}
 )
 
 var (
 	options Options
 	gr      git.Repository
+
+	// logger explains the decisions, it is configured by --verbose, --quiet and --log-format
+	logger = logging.New(os.Stderr, logging.LevelWarning, logging.TextFormat)
+
+	// changelogFile is the changelog file given on the command line
+	changelogFile string
+
+	// explicitOptions are the long names of the options given on the command
+	// line or in a configuration file, instead of taking their default
+	explicitOptions = make(map[string]bool)
 )
 
 // Options is a struct containing all the accepted command line options
 type Options struct {
-	Auto              bool   `short:"a" long:"auto" description:"autocomplete changelog from last snapshot or tag"`
-	Distribution      string `long:"distribution" description:"Set distribution" default:"unstable" value-name:"DISTRIBUTION"`
-	ForceBranch       string `long:"force-branch" description:"Force the branch name to use while generating the changelog" default:"" value-name:"branch"`
-	ForceDistribution bool   `long:"force-distribution" description:"Force the provided distribution to be used, even if it doesn't match the list of known distributions"`
-	GitAuthor         bool   `long:"git-author" description:"Use name and email from git-config for changelog trailer, default is 'False'"`
-	IgnoreMerges      bool   `long:"ignore-merges" description:"Ignore the merge commits in git history"`
-	NewVersion        string `short:"N" long:"new-version" description:"use this as base for the new version number" default:"" value-name:"NEW_VERSION"`
-	PurgeUnstable     bool   `long:"purge-unstable" description:"Purge from changelog file the old release unstable releases"`
-	PurgeTesting      bool   `long:"purge-testing" description:"Purge from changelog file the old release testing releases"`
-	Release           bool   `short:"R" long:"release" description:"mark as release"`
-	Since             string `long:"since" description:"commit to start from (e.g. HEAD^^^, debian/0.4.3)" default:"" value-name:"SINCE"`
-	Snapshot          bool   `short:"S" long:"snapshot" description:"mark as snapshot build"`
-	Urgency           string `long:"urgency" description:"Set urgency level" default:"medium" choice:"low" choice:"medium" choice:"high" choice:"emergency" choice:"critical" value-name:"URGENCY"`
-	Version           bool   `short:"v" long:"version" description:"show program's version number and exit"`
-
-	Args struct {
-		Filename string
-	} `positional-args:"yes"`
+	Auto              bool     `short:"a" long:"auto" description:"autocomplete changelog from last snapshot or tag"`
+	Backup            string   `long:"backup" description:"Keep a copy of the changelog file with this suffix before writing it" optional:"yes" optional-value:"~" value-name:"SUFFIX"`
+	BinaryOnly        bool     `long:"binary-only" description:"Mark the new changelog entry as binary-only upload"`
+	Color             string   `long:"color" description:"Color the diffs, the versions and the messages: always, never or when writing to a terminal without NO_COLOR set" choice:"auto" choice:"on" choice:"off" default:"auto"`
+	ColorScheme       string   `long:"color-scheme" description:"Colors as 'debug:info:warning:error:added:removed:hunk:version', each a name (e.g. red) or an SGR code (e.g. 1;33), empty for the default" default:"" value-name:"COLOR_SCHEME"`
+	Config            string   `long:"config" description:"Read options from this configuration file instead of debian/git-dch.conf and ~/.git-dch.conf" default:"" value-name:"FILE"`
+	Distribution      []string `long:"distribution" description:"Set distribution, repeat the option to target more distributions" default:"unstable" value-name:"DISTRIBUTION"`
+	DryRun            bool     `long:"dry-run" description:"Print the changes to the changelog as a diff, without writing it"`
+	DropReverts       bool     `long:"drop-reverts" description:"Omit the commits reverted in the same range, together with their revert"`
+	ExcludeAuthor     []string `long:"exclude-author" description:"Ignore the commits whose author ('Name <email>') matches the regular expression" value-name:"REGEX"`
+	ExcludeSubject    []string `long:"exclude-subject" description:"Ignore the commits whose subject matches the regular expression" value-name:"REGEX"`
+	ExcludeTrailer    []string `long:"exclude-trailer" description:"Ignore the commits with a matching trailer (e.g. 'Gbp-Dch: Ignore')" value-name:"TRAILER"`
+	ForceBranch       string   `long:"force-branch" description:"Force the branch name to use while generating the changelog" default:"" value-name:"branch"`
+	ForceDistribution bool     `long:"force-distribution" description:"Force the provided distribution to be used, even if it doesn't match the list of known distributions"`
+	GitAuthor         bool     `long:"git-author" description:"Prefer name and email from git-config over DEBFULLNAME/DEBEMAIL for changelog trailer, default is 'False'"`
+	IgnoreMerges      bool     `long:"ignore-merges" description:"Ignore the merge commits in git history"`
+	IncludeAuthor     []string `long:"include-author" description:"Use only the commits whose author ('Name <email>') matches the regular expression" value-name:"REGEX"`
+	IncludeSubject    []string `long:"include-subject" description:"Use only the commits whose subject matches the regular expression" value-name:"REGEX"`
+	IncludeTrailer    []string `long:"include-trailer" description:"Use only the commits with a matching trailer (e.g. 'Gbp-Dch: Full')" value-name:"TRAILER"`
+	Input             string   `long:"input" description:"Read the changelog from this file instead of FILE, '-' reads the standard input" value-name:"FILE"`
+	LenientSince      bool     `long:"lenient-since" description:"Accept a --since commit which does not exist or is not an ancestor of HEAD, listing the whole history"`
+	LogFormat         string   `long:"log-format" description:"Format of the messages on the standard error" choice:"text" choice:"json" default:"text"`
+	NewVersion        string   `short:"N" long:"new-version" description:"use this as base for the new version number" default:"" value-name:"NEW_VERSION"`
+	NoMergeUnreleased bool     `long:"no-merge-unreleased" description:"Add a new entry even when the entry on top of the changelog is UNRELEASED, instead of adding the new changes to it"`
+	Output            string   `long:"output" description:"Write the changelog to this file instead of the input one, '-' writes the standard output" value-name:"FILE"`
+	PurgeUnstable     bool     `long:"purge-unstable" description:"Purge from changelog file the old release unstable releases, as --unstable-history=purge"`
+	PurgeTesting      bool     `long:"purge-testing" description:"Purge from changelog file the old release testing releases, as --testing-history=purge"`
+	Quiet             bool     `long:"quiet" description:"Print only the errors"`
+	Release           bool     `short:"R" long:"release" description:"mark as release"`
+	Since             string   `long:"since" description:"commit to start from (e.g. HEAD^^^, HEAD~3, debian/0.4.3, @{upstream}), or a range of commits (e.g. v1.0..v1.1)" default:"" value-name:"SINCE"`
+	Snapshot          bool     `short:"S" long:"snapshot" description:"mark as snapshot build"`
+	SnapshotHistory   string   `long:"snapshot-history" description:"On release, keep the snapshot entries since the last release, squash their changes into the new entry or purge them" choice:"keep" choice:"squash" choice:"purge" default:"purge"`
+	SquashCherryPicks bool     `long:"squash-cherry-picks" description:"Report once the commits introducing the same changes (e.g. cherry-picks)"`
+	TestingHistory    string   `long:"testing-history" description:"Keep the testing entries since the last release, squash their changes into the new entry or purge them" choice:"keep" choice:"squash" choice:"purge" default:"keep"`
+	UnstableHistory   string   `long:"unstable-history" description:"Keep the unstable entries since the last release, squash their changes into the new entry or purge them" choice:"keep" choice:"squash" choice:"purge" default:"keep"`
+	Urgency           string   `long:"urgency" description:"Set urgency level: low, medium, high, emergency or critical, with an optional comment (e.g. 'high (security)')" default:"medium" value-name:"URGENCY"`
+	Verbose           bool     `long:"verbose" description:"Explain the start commit, the branch, the release type and the version chosen for the new entry"`
+	Version           bool     `short:"v" long:"version" description:"show program's version number and exit"`
+	WrapWidth         int      `long:"wrap-width" description:"Wrap the changelog lines longer than this width, 0 disables wrapping" default:"80" value-name:"WIDTH"`
+
+	Export         ExportCommand      `command:"export" description:"Export a changelog file to JSON, YAML, Markdown or RPM %changelog"`
+	Init           InitCommand        `command:"init" description:"Create a changelog file from the release tags or from a Keep a Changelog file"`
+	Lint           LintCommand        `command:"lint" description:"Check a changelog file for problems"`
+	MergeDriver    MergeDriverCommand `command:"merge-driver" description:"Merge three versions of a changelog file, as a git merge driver" long-description:"Run 'git-dch merge-driver --install' to use it, or add 'debian/changelog merge=git-dch' to .gitattributes and set merge.git-dch.driver to 'git-dch merge-driver %O %A %B' in the git configuration."`
+	ReleaseCommand ReleaseCommand     `command:"release" description:"Add a release entry to a changelog file, or finalize its UNRELEASED entry"`
+	Show           ShowCommand        `command:"show" description:"Print the fields of the last entries of a changelog file, like dpkg-parsechangelog"`
+}
+
+// The exit codes of the application, so that scripts can tell the kind of failure
+const (
+	exitFailure      = 1 // any other failure, the lint problems and the merge conflicts
+	exitUsage        = 2 // invalid command line, configuration or filter
+	exitChangelog    = 3 // the changelog file cannot be read, is empty or has invalid entries
+	exitVersion      = 4 // the version is not valid, goes backwards or does not match the branch
+	exitRepository   = 5 // the repository, the branch or the start commit cannot be found
+	exitDistribution = 6 // the distribution is not valid or not allowed for the branch
+)
+
+var (
+	// exitCodes maps the errors of the packages to the exit codes, the first match wins
+	exitCodes = []struct {
+		code   int
+		errors []error
+	}{
+		{exitDistribution, []error{changelog.ErrInvalidDistribution}},
+		{exitVersion, []error{
+			changelog.ErrVersionRegression,
+			dchversion.ErrInvalidVersion,
+			dchversion.ErrIncompatibleVersion,
+			dchversion.ErrNotSnapshot,
+		}},
+		{exitRepository, []error{
+			git.ErrNoRepository,
+			git.ErrNoBranch,
+			git.ErrCommitNotFound,
+			git.ErrNotAncestor,
+			git.ErrAmbiguousHash,
+			git.ErrInvalidRevision,
+			git.ErrNoUpstream,
+		}},
+		{exitChangelog, []error{
+			changelog.ErrEmptyChangelog,
+			changelog.ErrInvalidEntry,
+			changelog.ErrNoOpenEntry,
+			changelog.ErrNoReleases,
+		}},
+		{exitUsage, []error{git.ErrInvalidFilter, git.ErrConfig}},
+	}
+)
+
+// exitCodeError is an error terminating the application with a specific exit code
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode returns the exit code of the application for an error returned by RunApplication:
+// the code of an exitCodeError, or the code of the category of the error
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var e exitCodeError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	for _, category := range exitCodes {
+		for _, target := range category.errors {
+			if errors.Is(err, target) {
+				return category.code
+			}
+		}
+	}
+
+	return exitFailure
 }
 
 func RunApplication() (err error) {
-	if _, err = checkOptions(); err != nil {
-		return err
+	var command string
+	if command, err = checkOptions(); err != nil {
+		return exitCodeError{code: exitUsage, err: err}
+	}
+
+	switch command {
+	case "export":
+		return runExport()
+	case "init":
+		return runInit()
+	case "lint":
+		return runLint()
+	case "merge-driver":
+		return runMergeDriver()
+	case "release":
+		return runRelease()
+	case "show":
+		return runShow()
 	}
 
+	return runUpdate()
+}
+
+// runUpdate adds a new entry to the changelog file
+func runUpdate() (err error) {
 	if gr, err = git.NewRepositoryFromCurrentDirectory(); err != nil {
 		return err
 	}
+	gr.SetLogger(logger)
 
 	var author string
 	if author, err = getAuthor(); err != nil {
@@ -125,6 +305,7 @@ func RunApplication() (err error) {
 
 func isDistributionValidForBranch(distribution, branch string) bool {
 	var list []string
+	var stable bool
 	switch branch {
 	case "develop":
 		list = dUnstable
@@ -134,10 +315,21 @@ func isDistributionValidForBranch(distribution, branch string) bool {
 		fallthrough
 	case "release":
 		list = dStable
+		stable = true
 	default:
 		list = dUnstable
 	}
 
+	// stable releases can target the derived suites too
+	if stable {
+		for _, suffix := range dStableSuffixes {
+			if strings.HasSuffix(distribution, suffix) {
+				distribution = strings.TrimSuffix(distribution, suffix)
+				break
+			}
+		}
+	}
+
 	for _, d := range list {
 		if d == distribution {
 			return true
@@ -153,9 +345,30 @@ func printVersion() {
 	os.Exit(0)
 }
 
-func checkOptions() (args []string, err error) {
-	if args, err = flags.ParseArgs(&options, os.Args[1:]); err != nil {
-		return args, fmt.Errorf("cannot parse arguments on command line")
+// checkOptions parses the command line, returning the name of the
+// command to run or an empty string to update the changelog
+func checkOptions() (command string, err error) {
+	parser := flags.NewParser(&options, flags.Default)
+	parser.Usage = "[OPTIONS] [FILE]"
+	parser.SubcommandsOptional = true
+
+	var args []string
+	if args, err = parser.ParseArgs(os.Args[1:]); err != nil {
+		return command, fmt.Errorf("cannot parse arguments on command line")
+	}
+	if parser.Active != nil {
+		command = parser.Active.Name
+	}
+
+	if err = readConfigFiles(parser); err != nil {
+		return command, err
+	}
+
+	if err = configureLogger(); err != nil {
+		return command, err
+	}
+	if err = configureColors(); err != nil {
+		return command, err
 	}
 
 	if options.Version {
@@ -163,20 +376,246 @@ func checkOptions() (args []string, err error) {
 	}
 
 	if options.Auto && options.Since != "" {
-		return args, fmt.Errorf("options 'auto' and 'since' cannot be used together")
+		return command, fmt.Errorf("options 'auto' and 'since' cannot be used together")
+	}
+
+	if _, options.Urgency, err = changelog.NormalizeArgument("urgency", options.Urgency); err != nil {
+		return command, fmt.Errorf("the urgency %s is not valid", options.Urgency)
 	}
 
 	if options.Snapshot && options.Release {
-		return args, fmt.Errorf("options 'release'  and 'snapshot' cannot be used together")
+		return command, fmt.Errorf("options 'release'  and 'snapshot' cannot be used together")
 	}
 
-	if len(args) > 0 {
-		return args, fmt.Errorf("too many arguments on the command line")
+	if len(args) > 1 {
+		return command, fmt.Errorf("too many arguments on the command line")
+	}
+	if len(args) == 1 {
+		changelogFile = args[0]
+	}
+	if options.Input != "" && changelogFile != "" {
+		return command, fmt.Errorf("option 'input' and the FILE argument cannot be used together")
 	}
 
 	return
 }
 
+// configureLogger sets the level and the format of the logger from the options
+func configureLogger() error {
+	if options.Verbose && options.Quiet {
+		return fmt.Errorf("options 'verbose' and 'quiet' cannot be used together")
+	}
+
+	format, err := logging.ParseFormat(options.LogFormat)
+	if err != nil {
+		return err
+	}
+
+	level := logging.LevelWarning
+	switch {
+	case options.Verbose:
+		level = logging.LevelDebug
+	case options.Quiet:
+		level = logging.LevelError
+	}
+	logger = logging.New(os.Stderr, level, format)
+
+	return nil
+}
+
+// LogError reports an error returned by RunApplication, in the configured log format
+func LogError(err error) {
+	logger.Error(err.Error())
+}
+
+// printResult prints the outcome of a command, unless --quiet is given; it
+// goes to the standard error when the standard output holds the changelog
+func printResult(output string, format string, a ...interface{}) {
+	if options.Quiet {
+		return
+	}
+
+	message := os.Stdout
+	if output == standardStream {
+		message = os.Stderr
+	}
+	fmt.Fprintf(message, format+"\n", a...)
+}
+
+// printTransition prints the version of the new entry, highlighted; with
+// --verbose or --dry-run it is preceded by the version of the previous entry
+func printTransition(output, label, previous, version string) {
+	enabled := colorStdout
+	if output == standardStream {
+		enabled = colorStderr
+	}
+
+	text := paint(enabled, colors.version, version)
+	if previous != "" && (options.Verbose || options.DryRun) {
+		text = paint(enabled, colors.removed, previous) + " -> " + paint(enabled, colors.added, text)
+	}
+	printResult(output, "%s: %s", label, text)
+}
+
+// changelogState returns the text and the last version of a changelog, to
+// report the changes made to it
+func changelogState(f *changelog.File) (text, version string, err error) {
+	var b strings.Builder
+	if _, err = f.Write(&b); err != nil {
+		return
+	}
+	if !f.IsEmpty() {
+		var v dchversion.Version
+		if v, err = f.LastVersion(); err != nil {
+			return
+		}
+		version = v.String()
+	}
+
+	return b.String(), version, nil
+}
+
+// finishChangelog prints the changes made to the changelog and writes it,
+// unless --dry-run is given
+func finishChangelog(f *changelog.File, before, output string) error {
+	if err := printDiff(output, before, f); err != nil {
+		return err
+	}
+	if options.DryRun {
+		return nil
+	}
+
+	return writeChangelog(f, output)
+}
+
+// readConfigFiles reads the options not given on the command line from the
+// configuration files, which contain "long-option = value" lines. The files
+// are read from the highest priority, and the options already set by a file
+// are skipped in the next ones, so that repeated options are replaced and not
+// appended.
+func readConfigFiles(parser *flags.Parser) error {
+	files := standardConfigFiles
+	if options.Config != "" {
+		files = []string{options.Config}
+	}
+
+	// go-flags appends the values read to the default of a list option, so
+	// the defaults are removed and restored if no file sets the option
+	group := parser.Group.Find("Application Options")
+	var lists, defaults []reflect.Value
+	for _, opt := range group.Options() {
+		value := reflect.ValueOf(&options).Elem().FieldByIndex(opt.Field().Index)
+		if value.Kind() == reflect.Slice && opt.IsSetDefault() {
+			lists = append(lists, value)
+			defaults = append(defaults, reflect.ValueOf(value.Interface()))
+			value.Set(reflect.Zero(value.Type()))
+		}
+	}
+	defer func() {
+		for i, value := range lists {
+			if value.Len() == 0 {
+				value.Set(defaults[i])
+			}
+		}
+	}()
+
+	ini := flags.NewIniParser(parser)
+	ini.ParseAsDefaults = true
+	set := make(map[string]bool)
+	for _, file := range files {
+		if strings.HasPrefix(file, "~/") {
+			file = filepath.Join(os.Getenv("HOME"), file[2:])
+		}
+		file = filepath.FromSlash(file)
+		if _, err := os.Stat(file); os.IsNotExist(err) && options.Config == "" {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("cannot read configuration file %s: %w", file, err)
+		}
+		if err = ini.Parse(strings.NewReader(skipConfigOptions(string(data), set))); err != nil {
+			if iniErr, ok := err.(*flags.IniError); ok {
+				iniErr.File = file
+			}
+			return fmt.Errorf("cannot read configuration file %s: %w", file, err)
+		}
+	}
+
+	for _, opt := range group.Options() {
+		name := strings.ToLower(opt.LongName)
+		if !opt.IsSetDefault() || set["."+name] || set[strings.ToLower(group.ShortDescription)+"."+name] {
+			explicitOptions[opt.LongName] = true
+		}
+	}
+
+	return nil
+}
+
+// skipConfigOptions blanks the lines of a configuration file setting the
+// options in set, keeping the line numbers, and adds its options to set
+func skipConfigOptions(data string, set map[string]bool) string {
+	lines := strings.Split(data, "\n")
+	section := ""
+	found := make(map[string]bool)
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || line[0] == ';' || line[0] == '#':
+			continue
+		case line[0] == '[':
+			section = strings.ToLower(strings.TrimSpace(strings.Trim(line, "[]")))
+			continue
+		}
+
+		key := section + "." + strings.ToLower(strings.TrimSpace(strings.SplitN(line, "=", 2)[0]))
+		if set[key] {
+			lines[i] = ""
+			continue
+		}
+		found[key] = true
+	}
+	for key := range found {
+		set[key] = true
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func getFilter() (git.Filter, error) {
+	return git.NewFilter(
+		options.IncludeSubject, options.ExcludeSubject,
+		options.IncludeAuthor, options.ExcludeAuthor,
+		options.IncludeTrailer, options.ExcludeTrailer,
+	)
+}
+
+// configureFile applies to a changelog the options used to read the git history
+func configureFile(f *changelog.File) error {
+	filter, err := getFilter()
+	if err != nil {
+		return err
+	}
+	f.SetFilter(filter)
+	f.SetLogger(logger)
+	f.SetNetChanges(options.SquashCherryPicks, options.DropReverts)
+	f.SetWrapWidth(options.WrapWidth)
+	f.SetStrictSince(!options.LenientSince)
+	f.SetBackupSuffix(options.Backup)
+	f.SetMergeUnreleased(!options.NoMergeUnreleased)
+
+	var policies [3]changelog.HistoryPolicy
+	for i, value := range []string{options.SnapshotHistory, options.TestingHistory, options.UnstableHistory} {
+		if policies[i], err = changelog.ParseHistoryPolicy(value); err != nil {
+			return err
+		}
+	}
+	f.SetHistoryPolicies(policies[0], policies[1], policies[2])
+
+	return nil
+}
+
 func checkBranch(parsedVersion dchversion.Version, activeBranch string) error {
 	var err error
 
@@ -184,30 +623,90 @@ func checkBranch(parsedVersion dchversion.Version, activeBranch string) error {
 	// the value is compatible with the active branch
 	releaseForVersion := parsedVersion.Type()
 	if !parsedVersion.IsNative() && releaseForVersion.SourceBranch() != activeBranch {
-		return fmt.Errorf("cannot use version %s with branch %s", options.NewVersion, activeBranch)
+		return exitCodeError{code: exitVersion, err: fmt.Errorf("cannot use version %s with branch %s", options.NewVersion, activeBranch)}
 	}
 
-	if !isDistributionValidForBranch(options.Distribution, activeBranch) && !options.ForceDistribution {
-		return fmt.Errorf("the distribution %s is not valid for branch %s\n"+
-			"Use --force-distribution to use it anyway", options.Distribution, activeBranch)
+	for _, distribution := range options.Distribution {
+		if !isDistributionValidForBranch(distribution, activeBranch) && !options.ForceDistribution {
+			return exitCodeError{code: exitDistribution, err: fmt.Errorf("the distribution %s is not valid for branch %s\n"+
+				"Use --force-distribution to use it anyway", distribution, activeBranch)}
+		}
 	}
 
 	return err
 }
 
+// splitAuthor splits a value in the "Name <email>" format, as accepted by
+// dch in the DEBEMAIL and EMAIL variables
+func splitAuthor(value string) (name, email string) {
+	value = strings.TrimSpace(value)
+	if i := strings.Index(value, "<"); i >= 0 && strings.HasSuffix(value, ">") {
+		return strings.TrimSpace(value[:i]), strings.TrimSpace(value[i+1 : len(value)-1])
+	}
+
+	return "", value
+}
+
+// authorFromEnvironment returns the maintainer name and email using the
+// same variables as dch: DEBFULLNAME, DEBEMAIL, NAME and EMAIL
+func authorFromEnvironment() (name, email string) {
+	debName, debEmail := splitAuthor(os.Getenv("DEBEMAIL"))
+	plainName, plainEmail := splitAuthor(os.Getenv("EMAIL"))
+
+	name = firstNotEmpty(os.Getenv("DEBFULLNAME"), debName, os.Getenv("NAME"), plainName)
+	email = firstNotEmpty(debEmail, plainEmail)
+
+	return
+}
+
+// authorFromGit returns the maintainer name and email from the git configuration,
+// resolved through the system, global and local files
+func authorFromGit() (name, email string, err error) {
+	if name, _, err = gr.EffectiveConfigValue("user", "name"); err != nil {
+		return
+	}
+	email, _, err = gr.EffectiveConfigValue("user", "email")
+
+	return
+}
+
+func firstNotEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
 func getAuthor() (author string, err error) {
-	var name, email string
-	if name, err = gr.ConfigValue("user", "name"); err != nil {
-		return author, fmt.Errorf("cannot get user name from LOCAL git configuration: %s", err)
+	envName, envEmail := authorFromEnvironment()
+
+	// the git configuration is read only when the environment is not enough,
+	// so a broken configuration does not fail when DEBFULLNAME and DEBEMAIL are set
+	var gitName, gitEmail string
+	if options.GitAuthor || envName == "" || envEmail == "" {
+		if gitName, gitEmail, err = authorFromGit(); err != nil {
+			if envName == "" || envEmail == "" {
+				return author, fmt.Errorf("cannot get user from git configuration: %w", err)
+			}
+			err = nil
+		}
 	}
-	if email, err = gr.ConfigValue("user", "email"); err != nil {
-		return author, fmt.Errorf("cannot get user email from LOCAL git configuration: %s", err)
+
+	var name, email string
+	if options.GitAuthor {
+		name, email = firstNotEmpty(gitName, envName), firstNotEmpty(gitEmail, envEmail)
+	} else {
+		name, email = firstNotEmpty(envName, gitName), firstNotEmpty(envEmail, gitEmail)
 	}
+
 	if name == "" {
-		return author, fmt.Errorf("value of user.name in LOCAL git configuration is empty")
+		return author, fmt.Errorf("cannot find the maintainer name: set DEBFULLNAME or user.name in git configuration")
 	}
 	if email == "" {
-		return author, fmt.Errorf("value of user.email in LOCAL git configuration is empty")
+		return author, fmt.Errorf("cannot find the maintainer email: set DEBEMAIL or user.email in git configuration")
 	}
 	author = name + " <" + email + ">"
 
@@ -232,7 +731,7 @@ func getVersion(f *changelog.File) (parsedVersion dchversion.Version, err error)
 	var activeBranch string
 	// We get active branch
 	if activeBranch, err = gr.ActiveBranch(); err != nil && options.ForceBranch == "" {
-		return parsedVersion, fmt.Errorf("cannot get active branch from git: %s\n"+
+		return parsedVersion, fmt.Errorf("cannot get active branch from git: %w\n"+
 			"Use the --force-branch parameter to fix this error", err)
 	}
 
@@ -240,12 +739,14 @@ func getVersion(f *changelog.File) (parsedVersion dchversion.Version, err error)
 	if options.ForceBranch != "" {
 		activeBranch = options.ForceBranch
 	}
+	logger.Info("branch selected", logging.F("branch", activeBranch), logging.F("forced", options.ForceBranch != ""))
 
 	if !options.Snapshot {
 		// We build a valid version number for the active branch
 		releaseForBranch := dchversion.ReleaseTypeFromBranch(activeBranch)
+		logger.Info("release type detected from the branch", logging.F("branch", activeBranch), logging.F("type", releaseForBranch))
 		if parsedVersion, err = parsedVersion.Build(releaseForBranch); err != nil {
-			return parsedVersion, fmt.Errorf("cannot build a valid version for branch %s: %s", activeBranch, err)
+			return parsedVersion, fmt.Errorf("cannot build a valid version for branch %s: %w", activeBranch, err)
 		}
 	}
 
@@ -257,16 +758,55 @@ func getVersion(f *changelog.File) (parsedVersion dchversion.Version, err error)
 	return
 }
 
-func updateChangelog(author string) (err error) {
+// readChangelog reads the changelog from a file, or from the standard input
+// when the name is "-"
+func readChangelog(name string) (f *changelog.File, err error) {
 
-	filename := standardChangelogFile
-	if options.Args.Filename != "" {
-		filename = options.Args.Filename
+	if name == standardStream {
+		if f, err = changelog.New(os.Stdin); err != nil {
+			return nil, exitCodeError{code: exitChangelog, err: fmt.Errorf("cannot read changelog from standard input: %w", err)}
+		}
+		return
 	}
+
+	if f, err = changelog.NewFromFile(filepath.FromSlash(name)); err != nil {
+		return nil, exitCodeError{code: exitChangelog, err: fmt.Errorf("cannot open changelog file %s: %w", name, err)}
+	}
+
+	return
+}
+
+// writeChangelog writes the changelog to a file, or to the standard output
+// when the name is "-"
+func writeChangelog(f *changelog.File, name string) (err error) {
+
+	if name == standardStream {
+		_, err = f.Write(os.Stdout)
+		return
+	}
+
+	_, err = f.WriteToFile(filepath.FromSlash(name))
+	return
+}
+
+func updateChangelog(author string) (err error) {
+
+	input := firstNotEmpty(options.Input, changelogFile, standardChangelogFile)
+	output := firstNotEmpty(options.Output, input)
+
 	// We open the debian changelog file
 	var f *changelog.File
-	if f, err = changelog.NewFromFile(filepath.FromSlash(filename)); err != nil {
-		return fmt.Errorf("cannot open changelog file %s: %s", filename, err)
+	if f, err = readChangelog(input); err != nil {
+		return
+	}
+
+	if err = configureFile(f); err != nil {
+		return
+	}
+
+	var before, previous string
+	if before, previous, err = changelogState(f); err != nil {
+		return
 	}
 
 	var parsedVersion dchversion.Version
@@ -279,21 +819,27 @@ func updateChangelog(author string) (err error) {
 	case options.Snapshot:
 		v, _, err = f.AddSnapshot(options.Since, "", parsedVersion, author, options.Auto, options.IgnoreMerges)
 	case options.Release:
-		v, _, err = f.AddRelease(options.Since, "", parsedVersion, options.Urgency, options.Distribution, author,
+		v, _, err = f.AddRelease(options.Since, "", parsedVersion, options.Urgency, strings.Join(options.Distribution, " "), author,
 			options.Auto, options.IgnoreMerges, options.PurgeTesting, options.PurgeUnstable)
 	default:
-		v, _, err = f.Add(options.Since, "", parsedVersion, options.Urgency, options.Distribution, author,
+		v, _, err = f.Add(options.Since, "", parsedVersion, options.Urgency, strings.Join(options.Distribution, " "), author,
 			options.Auto, options.IgnoreMerges, options.PurgeTesting, options.PurgeUnstable)
 	}
 	if err != nil {
 		return
 	}
 
-	if _, err = f.WriteToFile(filepath.FromSlash(filename)); err != nil {
+	if options.BinaryOnly {
+		if err = f.SetArgument("binary-only", "yes"); err != nil {
+			return
+		}
+	}
+
+	if err = finishChangelog(f, before, output); err != nil {
 		return
 	}
 
-	fmt.Printf("New version: %s", v.String())
+	printTransition(output, "New version", previous, v.String())
 
 	return
 }
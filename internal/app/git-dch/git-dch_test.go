@@ -0,0 +1,141 @@
+package git_dch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jessevdk/go-flags"
+)
+
+func TestReadConfigFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "git-dch-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	high := filepath.Join(dir, "high.conf")
+	low := filepath.Join(dir, "low.conf")
+
+	tests := []struct {
+		name             string
+		args             []string
+		high             string
+		low              string
+		wantUrgency      string
+		wantDistribution []string
+		wantExplicit     bool
+	}{
+		{
+			name:             "defaults",
+			wantUrgency:      "medium",
+			wantDistribution: []string{"unstable"},
+		},
+		{
+			name:             "lower priority file only",
+			low:              "urgency = low\ndistribution = stable\n",
+			wantUrgency:      "low",
+			wantDistribution: []string{"stable"},
+			wantExplicit:     true,
+		},
+		{
+			name:             "conflicting options",
+			high:             "urgency = high\ndistribution = testing\ndistribution = experimental\n",
+			low:              "urgency = low\ndistribution = stable\n",
+			wantUrgency:      "high",
+			wantDistribution: []string{"testing", "experimental"},
+			wantExplicit:     true,
+		},
+		{
+			name:             "options in different files",
+			high:             "urgency = high\n",
+			low:              "# a comment\ndistribution = stable\n",
+			wantUrgency:      "high",
+			wantDistribution: []string{"stable"},
+			wantExplicit:     true,
+		},
+		{
+			name:             "command line",
+			args:             []string{"--urgency", "critical", "--distribution", "bionic"},
+			high:             "urgency = high\ndistribution = testing\n",
+			low:              "urgency = low\ndistribution = stable\n",
+			wantUrgency:      "critical",
+			wantDistribution: []string{"bionic"},
+			wantExplicit:     true,
+		},
+	}
+
+	saved := standardConfigFiles
+	defer func() { standardConfigFiles = saved }()
+	standardConfigFiles = []string{high, low}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for file, content := range map[string]string{high: tt.high, low: tt.low} {
+				os.Remove(file)
+				if content == "" {
+					continue
+				}
+				if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			options = Options{}
+			explicitOptions = make(map[string]bool)
+			parser := flags.NewParser(&options, flags.None)
+			parser.SubcommandsOptional = true
+			if _, err := parser.ParseArgs(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := readConfigFiles(parser); err != nil {
+				t.Fatalf("readConfigFiles() error = %v", err)
+			}
+			if options.Urgency != tt.wantUrgency {
+				t.Errorf("Urgency = %v, want %v", options.Urgency, tt.wantUrgency)
+			}
+			if !reflect.DeepEqual(options.Distribution, tt.wantDistribution) {
+				t.Errorf("Distribution = %v, want %v", options.Distribution, tt.wantDistribution)
+			}
+			for _, name := range []string{"urgency", "distribution"} {
+				if explicitOptions[name] != tt.wantExplicit {
+					t.Errorf("explicitOptions[%s] = %v, want %v", name, explicitOptions[name], tt.wantExplicit)
+				}
+			}
+		})
+	}
+}
+
+func TestIsDistributionValidForBranch(t *testing.T) {
+	tests := []struct {
+		name         string
+		distribution string
+		branch       string
+		want         bool
+	}{
+		{name: "stable", distribution: "bookworm", branch: "release", want: true},
+		{name: "security", distribution: "bookworm-security", branch: "release", want: true},
+		{name: "updates", distribution: "jammy-updates", branch: "master", want: true},
+		{name: "proposed updates", distribution: "bookworm-proposed-updates", branch: "release", want: true},
+		{name: "proposed", distribution: "jammy-proposed", branch: "release", want: true},
+		{name: "backports", distribution: "bookworm-backports", branch: "release", want: true},
+		{name: "backports sloppy", distribution: "bookworm-backports-sloppy", branch: "release", want: true},
+		{name: "unknown suite", distribution: "bookworm-nightly", branch: "release", want: false},
+		{name: "unknown base", distribution: "nowhere-security", branch: "release", want: false},
+		{name: "suffix only", distribution: "-security", branch: "release", want: false},
+		{name: "suffix on develop", distribution: "bookworm-security", branch: "develop", want: false},
+		{name: "unstable on develop", distribution: "unstable", branch: "develop", want: true},
+		{name: "testing on staging", distribution: "testing", branch: "staging", want: true},
+		{name: "suffix on staging", distribution: "testing-security", branch: "staging", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDistributionValidForBranch(tt.distribution, tt.branch); got != tt.want {
+				t.Errorf("isDistributionValidForBranch(%s, %s) = %v, want %v", tt.distribution, tt.branch, got, tt.want)
+			}
+		})
+	}
+}
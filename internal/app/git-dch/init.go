@@ -0,0 +1,82 @@
+package git_dch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cinello/git-dch/pkg/changelog"
+	"github.com/cinello/git-dch/pkg/git"
+)
+
+// InitCommand contains the options of the init command
+type InitCommand struct {
+	Force        bool   `long:"force" description:"Overwrite an existing changelog file"`
+	FromMarkdown string `long:"from-markdown" description:"Read the releases from a Keep a Changelog file instead of the git tags" default:"" value-name:"FILE"`
+	Source       string `long:"source" description:"Source package name, default is the name of the current directory" default:"" value-name:"SOURCE"`
+
+	Args struct {
+		Filename string
+	} `positional-args:"yes"`
+}
+
+// runInit creates a new changelog file with an entry for each release,
+// read from the git tags or from a markdown changelog
+func runInit() (err error) {
+	filename := firstNotEmpty(options.Init.Args.Filename, standardChangelogFile)
+	if _, err = os.Stat(filepath.FromSlash(filename)); err == nil && !options.Init.Force {
+		return fmt.Errorf("the changelog file %s already exists, use --force to overwrite it", filename)
+	}
+
+	var f *changelog.File
+	if f, err = changelog.New(strings.NewReader("")); err != nil {
+		return
+	}
+	if err = configureFile(f); err != nil {
+		return
+	}
+
+	target := strings.Join(options.Distribution, " ")
+	if options.Init.FromMarkdown == "" {
+		err = f.InitFromTags(options.Init.Source, target, options.Urgency, options.IgnoreMerges)
+	} else {
+		err = initFromMarkdown(f, target)
+	}
+	if err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(filepath.FromSlash(filename)), 0755); err != nil {
+		return
+	}
+	if _, err = f.WriteToFile(filepath.FromSlash(filename)); err != nil {
+		return
+	}
+
+	printResult(filename, "Created %s with %d entries", filename, f.Len())
+
+	return
+}
+
+// initFromMarkdown fills the changelog from a markdown file, the maintainer
+// of all the entries is the current user
+func initFromMarkdown(f *changelog.File, target string) (err error) {
+	if gr, err = git.NewRepositoryFromCurrentDirectory(); err != nil {
+		return
+	}
+	gr.SetLogger(logger)
+
+	var author string
+	if author, err = getAuthor(); err != nil {
+		return
+	}
+
+	var markdown *os.File
+	if markdown, err = os.Open(filepath.FromSlash(options.Init.FromMarkdown)); err != nil {
+		return fmt.Errorf("cannot open markdown file %s: %w", options.Init.FromMarkdown, err)
+	}
+	defer markdown.Close()
+
+	return f.InitFromMarkdown(markdown, options.Init.Source, target, options.Urgency, author)
+}
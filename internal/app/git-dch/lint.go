@@ -0,0 +1,85 @@
+package git_dch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cinello/git-dch/pkg/changelog"
+)
+
+// LintCommand contains the options of the lint command
+type LintCommand struct {
+	Format        string `long:"format" description:"Output format" choice:"text" choice:"json" default:"text"`
+	MaxLineLength int    `long:"max-line-length" description:"Report the changes lines longer than this length, 0 disables the check" default:"80" value-name:"LENGTH"`
+	Strict        bool   `long:"strict" description:"Fail on warnings too"`
+
+	Args struct {
+		Filename string
+	} `positional-args:"yes"`
+}
+
+// lintReport is the JSON output of the lint command
+type lintReport struct {
+	File     string              `json:"file"`
+	Errors   int                 `json:"errors"`
+	Warnings int                 `json:"warnings"`
+	Problems []changelog.Problem `json:"problems"`
+}
+
+// isDistributionKnown reports if a distribution is valid for any branch
+func isDistributionKnown(distribution string) bool {
+	for _, branch := range []string{"develop", "staging", "release"} {
+		if isDistributionValidForBranch(distribution, branch) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runLint checks a changelog file and prints the problems found. It fails
+// with exitFailure if the file contains errors (or warnings, in strict mode),
+// and with exitChangelog if the file cannot be read.
+func runLint() (err error) {
+	filename := firstNotEmpty(options.Lint.Args.Filename, standardChangelogFile)
+
+	var problems []changelog.Problem
+	lintOptions := changelog.LintOptions{
+		MaxLineLength:       options.Lint.MaxLineLength,
+		IsDistributionKnown: isDistributionKnown,
+	}
+	if problems, err = changelog.LintFile(filepath.FromSlash(filename), lintOptions); err != nil {
+		return exitCodeError{code: exitChangelog, err: fmt.Errorf("cannot read changelog file %s: %w", filename, err)}
+	}
+
+	report := lintReport{File: filename, Problems: []changelog.Problem{}}
+	for _, p := range problems {
+		if p.Severity == changelog.SeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+		report.Problems = append(report.Problems, p)
+	}
+
+	if options.Lint.Format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(report); err != nil {
+			return
+		}
+	} else {
+		for _, p := range problems {
+			fmt.Printf("%s:%s\n", filename, p.String())
+		}
+	}
+
+	if report.Errors > 0 || (options.Lint.Strict && report.Warnings > 0) {
+		return exitCodeError{code: exitFailure, err: fmt.Errorf("changelog file %s has %d errors and %d warnings",
+			filename, report.Errors, report.Warnings)}
+	}
+
+	return nil
+}
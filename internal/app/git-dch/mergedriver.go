@@ -0,0 +1,103 @@
+package git_dch
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cinello/git-dch/pkg/changelog"
+	"github.com/cinello/git-dch/pkg/git"
+)
+
+const (
+	mergeDriverName      = "git-dch"
+	mergeDriverCommand   = "git-dch merge-driver %O %A %B"
+	mergeDriverAttribute = "debian/changelog merge=" + mergeDriverName
+)
+
+// MergeDriverCommand contains the options of the merge-driver command
+type MergeDriverCommand struct {
+	Install bool `long:"install" description:"Configure the repository containing the current directory to merge debian/changelog with git-dch"`
+
+	Args struct {
+		Base   string `positional-arg-name:"BASE"`
+		Ours   string `positional-arg-name:"OURS"`
+		Theirs string `positional-arg-name:"THEIRS"`
+	} `positional-args:"yes"`
+}
+
+// runMergeDriver merges the changelog files given by git as %O %A %B,
+// writing the result in the %A file. It fails with exit code 1 when
+// the merge has conflicts, as git expects.
+func runMergeDriver() (err error) {
+	if options.MergeDriver.Install {
+		return installMergeDriver()
+	}
+
+	args := options.MergeDriver.Args
+	if args.Base == "" || args.Ours == "" || args.Theirs == "" {
+		return exitCodeError{code: exitUsage, err: fmt.Errorf("the merge driver needs the base, ours and theirs files")}
+	}
+
+	var items [3]changelog.Items
+	for i, filename := range []string{args.Base, args.Ours, args.Theirs} {
+		if items[i], err = changelog.NewItemListFromFile(filepath.FromSlash(filename)); err != nil {
+			return fmt.Errorf("cannot read changelog file %s: %w", filename, err)
+		}
+	}
+
+	text, conflicts := changelog.Merge(items[0], items[1], items[2])
+	if err = ioutil.WriteFile(filepath.FromSlash(args.Ours), []byte(text), 0644); err != nil {
+		return
+	}
+
+	if len(conflicts) > 0 {
+		return exitCodeError{code: exitFailure, err: fmt.Errorf("conflicts in the changelog entries of versions %s",
+			strings.Join(conflicts, ", "))}
+	}
+
+	return nil
+}
+
+// installMergeDriver registers the merge driver in the local git
+// configuration and assigns it to debian/changelog in the .gitattributes
+// file at the root of the working tree
+func installMergeDriver() (err error) {
+	var dir, root string
+	if dir, err = os.Getwd(); err != nil {
+		return
+	}
+	if gr, err = git.FindRepository(dir); err != nil {
+		return
+	}
+	if root, err = gr.WorktreeRoot(); err != nil {
+		return
+	}
+	if err = gr.SetConfigSubsectionValue("merge", mergeDriverName, "name", "debian/changelog merge driver"); err != nil {
+		return
+	}
+	if err = gr.SetConfigSubsectionValue("merge", mergeDriverName, "driver", mergeDriverCommand); err != nil {
+		return
+	}
+
+	attributes := filepath.Join(root, ".gitattributes")
+	var data []byte
+	if data, err = ioutil.ReadFile(attributes); err != nil && !os.IsNotExist(err) {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.Join(strings.Fields(line), " ") == mergeDriverAttribute {
+			return nil
+		}
+	}
+
+	text := string(data)
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	text += mergeDriverAttribute + "\n"
+
+	return ioutil.WriteFile(attributes, []byte(text), 0644)
+}
@@ -0,0 +1,87 @@
+package git_dch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cinello/git-dch/pkg/changelog"
+	"github.com/cinello/git-dch/pkg/dchversion"
+)
+
+// ReleaseCommand contains the options of the release command
+type ReleaseCommand struct {
+	Finalize bool `long:"finalize" description:"Release the UNRELEASED entry on top of the changelog keeping its text, like dch -r, instead of adding a new entry"`
+
+	Args struct {
+		Filename string
+	} `positional-args:"yes"`
+}
+
+// runRelease adds a release entry to the changelog, as the --release
+// option, or finalizes the UNRELEASED entry on top of it
+func runRelease() (err error) {
+	if !options.ReleaseCommand.Finalize {
+		if options.Snapshot {
+			return exitCodeError{code: exitUsage, err: fmt.Errorf("the release command and the option 'snapshot' cannot be used together")}
+		}
+		if options.Input != "" && options.ReleaseCommand.Args.Filename != "" {
+			return exitCodeError{code: exitUsage, err: fmt.Errorf("option 'input' and the FILE argument cannot be used together")}
+		}
+		options.Release = true
+		changelogFile = options.ReleaseCommand.Args.Filename
+
+		return runUpdate()
+	}
+
+	input := firstNotEmpty(options.Input, options.ReleaseCommand.Args.Filename, standardChangelogFile)
+	output := firstNotEmpty(options.Output, input)
+
+	var f *changelog.File
+	if f, err = readChangelog(input); err != nil {
+		return
+	}
+	f.SetBackupSuffix(options.Backup)
+
+	var before, previous string
+	if before, previous, err = changelogState(f); err != nil {
+		return
+	}
+
+	var ver dchversion.Version
+	if options.NewVersion != "" {
+		if ver, err = dchversion.Parse(options.NewVersion); err != nil {
+			return
+		}
+	} else if ver, err = f.LastVersion(); err != nil {
+		return
+	}
+
+	// As dch -r, the entry keeps its urgency and takes the distribution of the
+	// previous one, unless they are given
+	var urgency, target string
+	if explicitOptions["urgency"] {
+		urgency = options.Urgency
+	}
+	if explicitOptions["distribution"] {
+		for _, distribution := range options.Distribution {
+			if !isDistributionKnown(distribution) && !options.ForceDistribution {
+				return exitCodeError{code: exitDistribution, err: fmt.Errorf("the distribution %s is not known\n"+
+					"Use --force-distribution to use it anyway", distribution)}
+			}
+		}
+		target = strings.Join(options.Distribution, " ")
+	}
+
+	var v dchversion.Version
+	if v, err = f.Finalize(ver, urgency, target); err != nil {
+		return
+	}
+
+	if err = finishChangelog(f, before, output); err != nil {
+		return
+	}
+
+	printTransition(output, "Released version", previous, v.String())
+
+	return nil
+}
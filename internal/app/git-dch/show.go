@@ -0,0 +1,103 @@
+package git_dch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cinello/git-dch/pkg/changelog"
+)
+
+// ShowCommand contains the options of the show command
+type ShowCommand struct {
+	Count  int    `long:"count" description:"Show the changes of the last N entries" default:"0" value-name:"N"`
+	Field  string `long:"field" description:"Print only the value of a field: Source, Version, Distribution, Urgency, Maintainer, Date, Timestamp, Changes or Closes" default:"" value-name:"FIELD"`
+	Format string `long:"format" description:"Output format" choice:"deb822" choice:"json" default:"deb822"`
+	Since  string `long:"since" description:"Show the changes of the versions greater than VERSION" default:"" value-name:"VERSION"`
+	Until  string `long:"until" description:"Show the changes of the versions lower than VERSION" default:"" value-name:"VERSION"`
+
+	Args struct {
+		Filename string
+	} `positional-args:"yes"`
+}
+
+// runShow prints the fields of the entries of a changelog file,
+// like dpkg-parsechangelog
+func runShow() (err error) {
+	filename := firstNotEmpty(options.Show.Args.Filename, standardChangelogFile)
+
+	if options.Show.Field != "" && !isSummaryField(options.Show.Field) {
+		return exitCodeError{code: exitUsage, err: fmt.Errorf("unknown field %s, must be one of %s", options.Show.Field, strings.Join(changelog.SummaryFields, ", "))}
+	}
+
+	var f *changelog.File
+	if f, err = changelog.NewFromFile(filepath.FromSlash(filename)); err != nil {
+		return exitCodeError{code: exitChangelog, err: fmt.Errorf("cannot open changelog file %s: %w", filename, err)}
+	}
+
+	var summary changelog.Summary
+	if summary, err = f.Summary(options.Show.Count, options.Show.Since, options.Show.Until); err != nil {
+		return
+	}
+
+	if options.Show.Format == "json" {
+		return printSummaryJSON(summary, options.Show.Field)
+	}
+
+	if options.Show.Field == "" {
+		fmt.Print(summary.Deb822())
+		return nil
+	}
+
+	var value string
+	if value, err = summary.Field(options.Show.Field); err != nil {
+		return
+	}
+	if strings.EqualFold(options.Show.Field, "Changes") {
+		// like dpkg-parsechangelog, the value keeps the deb822 format
+		value = "\n" + changelog.FoldFieldValue(value)
+	}
+	fmt.Println(value)
+
+	return nil
+}
+
+// isSummaryField reports if name is the name of a summary field, the case is ignored
+func isSummaryField(name string) bool {
+	for _, field := range changelog.SummaryFields {
+		if strings.EqualFold(field, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// printSummaryJSON prints the summary, or only one of its fields, as a JSON object
+func printSummaryJSON(summary changelog.Summary, field string) (err error) {
+	var value interface{} = summary
+	if field != "" {
+		if _, err = summary.Field(field); err != nil {
+			return
+		}
+
+		var data []byte
+		if data, err = json.Marshal(summary); err != nil {
+			return
+		}
+		var fields map[string]json.RawMessage
+		if err = json.Unmarshal(data, &fields); err != nil {
+			return
+		}
+		key := strings.ToLower(field)
+		value = map[string]json.RawMessage{key: fields[key]}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
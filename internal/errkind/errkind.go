@@ -0,0 +1,51 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package errkind creates the errors of the git-dch packages, which match the
+// sentinel error of their category with errors.Is
+package errkind
+
+import (
+	"errors"
+	"fmt"
+)
+
+// kindError is an error with its own message which matches one of the
+// sentinel errors with errors.Is, while still wrapping its cause
+type kindError struct {
+	kind error
+	err  error
+}
+
+// New returns an error of the given kind, formatted as fmt.Errorf does
+func New(kind error, format string, a ...interface{}) error {
+
+	return &kindError{kind: kind, err: fmt.Errorf(format, a...)}
+}
+
+func (e *kindError) Error() string {
+
+	return e.err.Error()
+}
+
+func (e *kindError) Is(target error) bool {
+
+	return target == e.kind
+}
+
+func (e *kindError) Unwrap() error {
+
+	return errors.Unwrap(e.err)
+}
@@ -0,0 +1,235 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffLine is a line of a diff: ' ' for a common line, '-' for a removed line
+// and '+' for an added one
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// diffLines returns the lines of a text, without the final newline
+func diffLines(text string) []string {
+
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffOperations returns the lines of both texts, marked as common, removed or
+// added, the removed lines before the added ones. The common head and tail are
+// skipped before the longest common subsequence is searched, as the new entries
+// are added on top of a changelog and the old entries are rarely changed.
+func diffOperations(a, b []string) (ops []diffLine) {
+
+	head := 0
+	for head < len(a) && head < len(b) && a[head] == b[head] {
+		head++
+	}
+	tail := 0
+	for tail < len(a)-head && tail < len(b)-head && a[len(a)-1-tail] == b[len(b)-1-tail] {
+		tail++
+	}
+
+	for _, line := range a[:head] {
+		ops = append(ops, diffLine{' ', line})
+	}
+
+	ma, mb := a[head:len(a)-tail], b[head:len(b)-tail]
+	i, j := 0, 0
+	for _, m := range commonLines(ma, mb, 0, 0, nil) {
+		for ; i < m[0]; i++ {
+			ops = append(ops, diffLine{'-', ma[i]})
+		}
+		for ; j < m[1]; j++ {
+			ops = append(ops, diffLine{'+', mb[j]})
+		}
+		ops = append(ops, diffLine{' ', ma[i]})
+		i++
+		j++
+	}
+	for ; i < len(ma); i++ {
+		ops = append(ops, diffLine{'-', ma[i]})
+	}
+	for ; j < len(mb); j++ {
+		ops = append(ops, diffLine{'+', mb[j]})
+	}
+
+	for _, line := range a[len(a)-tail:] {
+		ops = append(ops, diffLine{' ', line})
+	}
+
+	return
+}
+
+// commonLines appends to matches the indexes, offset by ia and ib, of the lines
+// of a longest common subsequence of a and b. It splits a in halves and b where
+// the sum of the subsequences of both halves is the longest (Hirschberg), so
+// that the memory used is linear in the length of b.
+func commonLines(a, b []string, ia, ib int, matches [][2]int) [][2]int {
+
+	switch {
+	case len(a) == 0 || len(b) == 0:
+		return matches
+	case len(a) == 1:
+		for j, line := range b {
+			if line == a[0] {
+				return append(matches, [2]int{ia, ib + j})
+			}
+		}
+		return matches
+	}
+
+	mid := len(a) / 2
+	forward := lcsLengths(a[:mid], b, false)
+	backward := lcsLengths(a[mid:], b, true)
+	split := 0
+	for j := range forward {
+		if forward[j]+backward[j] > forward[split]+backward[split] {
+			split = j
+		}
+	}
+
+	matches = commonLines(a[:mid], b[:split], ia, ib, matches)
+	return commonLines(a[mid:], b[split:], ia+mid, ib+split, matches)
+}
+
+// lcsLengths returns, for each j, the length of the longest common subsequence
+// of a and b[:j] or, in reverse, of a and b[j:]
+func lcsLengths(a, b []string, reverse bool) []int {
+
+	row := make([]int, len(b)+1)
+	for n := range a {
+		// diagonal is the value of the previous row, before it is replaced
+		diagonal := 0
+		if !reverse {
+			for j := 1; j <= len(b); j++ {
+				previous := row[j]
+				if a[n] == b[j-1] {
+					row[j] = diagonal + 1
+				} else if row[j-1] > row[j] {
+					row[j] = row[j-1]
+				}
+				diagonal = previous
+			}
+			continue
+		}
+
+		i := len(a) - 1 - n
+		for j := len(b) - 1; j >= 0; j-- {
+			previous := row[j]
+			if a[i] == b[j] {
+				row[j] = diagonal + 1
+			} else if row[j+1] > row[j] {
+				row[j] = row[j+1]
+			}
+			diagonal = previous
+		}
+	}
+
+	return row
+}
+
+// Diff returns the unified diff between two versions of a changelog, with the
+// given number of context lines around the changes; it is empty if the texts
+// are equal. The name is used in the --- and +++ header lines.
+func Diff(name, before, after string, context int) string {
+
+	ops := diffOperations(diffLines(before), diffLines(after))
+
+	// the ranges of operations shown in each hunk, merging the close ones
+	var hunks [][2]int
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		start, end := i-context, i+context+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(ops) {
+			end = len(ops)
+		}
+		if n := len(hunks); n > 0 && start <= hunks[n-1][1] {
+			hunks[n-1][1] = end
+			continue
+		}
+		hunks = append(hunks, [2]int{start, end})
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", name, name)
+	oldLine, newLine, next := 1, 1, 0
+	for _, h := range hunks {
+		// count the lines before the hunk
+		for ; next < h[0]; next++ {
+			oldLine++
+			newLine++
+			if ops[next].kind == '+' {
+				oldLine--
+			} else if ops[next].kind == '-' {
+				newLine--
+			}
+		}
+
+		var oldCount, newCount int
+		for _, op := range ops[h[0]:h[1]] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+		for _, op := range ops[h[0]:h[1]] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.text)
+			b.WriteByte('\n')
+		}
+		oldLine += oldCount
+		newLine += newCount
+		next = h[1]
+	}
+
+	return b.String()
+}
+
+// hunkRange returns the range of a hunk header: the start line, followed by
+// the number of lines when it is not one; an empty range starts at the
+// previous line
+func hunkRange(start, count int) string {
+
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
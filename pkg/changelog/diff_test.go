@@ -0,0 +1,51 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{name: `equal`, before: "a\nb\n", after: "a\nb\n", want: ""},
+		{
+			name:   `newEntry`,
+			before: "a\nb\nc\nd\n",
+			after:  "x\n\na\nb\nc\nd\n",
+			want:   "--- a/changelog\n+++ b/changelog\n@@ -1,3 +1,5 @@\n+x\n+\n a\n b\n c\n",
+		},
+		{
+			name:   `twoHunks`,
+			before: "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n",
+			after:  "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nM\nn\n",
+			want: "--- a/changelog\n+++ b/changelog\n@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+				"@@ -10,5 +10,5 @@\n j\n k\n l\n-m\n+M\n n\n",
+		},
+		{name: `empty`, before: "", after: "a\n", want: "--- a/changelog\n+++ b/changelog\n@@ -0,0 +1 @@\n+a\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff("changelog", tt.before, tt.after, 3); got != tt.want {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,20 +16,39 @@
 package changelog
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/cinello/git-dch/internal/errkind"
 	"github.com/cinello/git-dch/pkg/dchversion"
 
 	"github.com/cinello/go-debian/changelog"
 	"github.com/cinello/go-debian/version"
 )
 
+var (
+	regExUrgency = regexp.MustCompile(`(?i)^(low|medium|high|emergency|critical)(\s+\([^()]*\))?$`)
+)
+
 // Item encapsulate a debian changelog entry
 type Item struct {
 	changelog.ChangelogEntry
+
+	// raw is the original text of an entry read from a changelog, and
+	// parsed the values read from it: while they don't change, the
+	// entry is written back exactly as it was read
+	raw    string
+	parsed changelog.ChangelogEntry
+
+	// keywords is the order of the header keywords (the Arguments keys)
+	keywords []string
 }
 
 // NewItem creates a new Item variable, this function accept the following parameters:
@@ -94,7 +113,7 @@ func isStringValid(value string) bool {
 func (e *Item) SetSource(value string) error {
 
 	if !isStringValid(value) {
-		return fmt.Errorf("changelog Source %s is not valid", value)
+		return errkind.New(ErrInvalidEntry, "changelog Source %s is not valid", value)
 	}
 
 	e.Source = value
@@ -118,7 +137,7 @@ func isVersionValid(value version.Version) bool {
 func (e *Item) SetVersion(value version.Version) error {
 
 	if !isVersionValid(value) {
-		return fmt.Errorf("changelog Version %s is not valid", value.String())
+		return errkind.New(ErrInvalidEntry, "changelog Version %s is not valid", value.String())
 	}
 
 	e.Version = value
@@ -126,32 +145,66 @@ func (e *Item) SetVersion(value version.Version) error {
 	return nil
 }
 
-// SetTarget method change the Target field of an Item variable
+// SetTarget method change the Target field of an Item variable.
+// The value can contain more distributions separated by spaces.
 func (e *Item) SetTarget(value string) error {
 
-	if !isStringValid(value) {
-		return fmt.Errorf("changelog Target %s is not valid", value)
+	if err := e.SetTargets(strings.Fields(value)...); err != nil {
+		return errkind.New(ErrInvalidDistribution, "changelog Target %s is not valid", value)
 	}
 
-	e.Target = value
+	return nil
+}
+
+// SetTargets method change the list of target distributions of an Item variable
+func (e *Item) SetTargets(values ...string) error {
+
+	if !areTargetsValid(values) {
+		return errkind.New(ErrInvalidDistribution, "changelog Target %s is not valid", strings.Join(values, " "))
+	}
+
+	e.Target = strings.Join(values, " ")
 
 	return nil
 }
 
-// SetUrgency method change the Urgency field of an Item variable
+// Targets method returns the list of target distributions of an Item variable,
+// read from the Target field
+func (e *Item) Targets() []string {
+
+	return strings.Fields(e.Target)
+}
+
+// areTargetsValid is used to validate the list of target distributions
+// of a changelog entry: the list must not be empty and each distribution
+// must be a string without spaces.
+func areTargetsValid(values []string) bool {
+
+	if len(values) == 0 {
+		return false
+	}
+
+	for _, v := range values {
+		if !isStringValid(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SetUrgency method change the Urgency field of an Item variable.
+// The urgency can be followed by a comment, as in "high (security)"
 func (e *Item) SetUrgency(value string) error {
 
 	if value == "" {
-		e.Arguments["urgency"] = "medium"
-		return nil
+		value = "medium"
 	}
 
-	if !isStringValid(value) {
-		return fmt.Errorf("changelog Urgency %s is not valid", value)
+	if err := e.SetArgument("urgency", value); err != nil {
+		return errkind.New(ErrInvalidEntry, "changelog Urgency %s is not valid", value)
 	}
 
-	e.Arguments["urgency"] = value
-
 	return nil
 }
 
@@ -189,7 +242,7 @@ func isAuthorValid(value string) bool {
 func (e *Item) SetAuthor(value string) error {
 
 	if !isAuthorValid(value) {
-		return fmt.Errorf("changelog author cannot be empty")
+		return errkind.New(ErrInvalidEntry, "changelog author cannot be empty")
 	}
 
 	e.ChangedBy = value
@@ -217,29 +270,112 @@ func WhenToString(t time.Time) string {
 	return t.Format(time.RFC1123Z)
 }
 
+// NormalizeArgument validates a keyword of a changelog entry header, returning
+// the key and the value with their canonical casing. The known keywords are:
+//
+//	urgency:     low, medium, high, emergency or critical, optionally
+//	             followed by a comment in parentheses
+//	binary-only: yes
+//
+// Any other keyword must have a key and a value without spaces.
+func NormalizeArgument(key, value string) (string, string, error) {
+
+	switch strings.ToLower(key) {
+	case "urgency":
+		values := regExUrgency.FindStringSubmatch(value)
+		if values == nil {
+			return key, value, errkind.New(ErrInvalidEntry, "changelog argument value %s for key %s is not valid", value, key)
+		}
+		return "urgency", strings.ToLower(values[1]) + values[2], nil
+	case "binary-only":
+		if !strings.EqualFold(value, "yes") {
+			return key, value, errkind.New(ErrInvalidEntry, "changelog argument value %s for key %s is not valid", value, key)
+		}
+		return "binary-only", "yes", nil
+	}
+
+	if !isStringValid(key) || strings.ContainsAny(key, "=,;") {
+		return key, value, errkind.New(ErrInvalidEntry, "changelog argument %s key is not valid", key)
+	}
+
+	if !isStringValid(value) || strings.ContainsAny(value, ",;") {
+		return key, value, errkind.New(ErrInvalidEntry, "changelog argument value %s for key %s is not valid", value, key)
+	}
+
+	return key, value, nil
+}
+
 // SetArgument method create or change an Argument in an Item
 // variable. Urgency is a standard argument, other not standard
-// arguments can be created by this method
-func (e *Item) SetArgument(key, value string) error {
+// arguments can be created by this method. The keywords are
+// written in the order they are created.
+func (e *Item) SetArgument(key, value string) (err error) {
 
-	if !isStringValid(key) {
-		return fmt.Errorf("changelog argument %s key is not valid", key)
+	if key, value, err = NormalizeArgument(key, value); err != nil {
+		return
 	}
 
-	if !isStringValid(value) {
-		return fmt.Errorf("changelog argument value %s for key %s is not valid", value, key)
+	if e.Arguments == nil {
+		e.Arguments = make(map[string]string)
+	}
+	if _, ok := e.Arguments[key]; !ok {
+		e.keywords = append(e.keywords, key)
 	}
-
 	e.Arguments[key] = value
 
 	return nil
 }
 
+// RemoveArgument method delete an Argument from an Item variable
+func (e *Item) RemoveArgument(key string) {
+
+	delete(e.Arguments, key)
+	for i, k := range e.keywords {
+		if k == key {
+			e.keywords = append(e.keywords[:i:i], e.keywords[i+1:]...)
+			break
+		}
+	}
+}
+
+// Keywords returns the keys of the Arguments in the order they are written:
+// first the ones read from the changelog or set by SetArgument, then the
+// other ones, urgency first and then sorted by name
+func (e *Item) Keywords() (keys []string) {
+
+	seen := make(map[string]bool)
+	for _, k := range e.keywords {
+		if _, ok := e.Arguments[k]; ok && !seen[k] {
+			keys = append(keys, k)
+			seen[k] = true
+		}
+	}
+
+	var others []string
+	for k := range e.Arguments {
+		if !seen[k] && k != "" {
+			others = append(others, k)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool {
+		if others[i] == "urgency" || others[j] == "urgency" {
+			return others[i] == "urgency"
+		}
+		return others[i] < others[j]
+	})
+
+	return append(keys, others...)
+}
+
 // areArgumentsValid is used to validate all the arguments of an Item variable.
 func areArgumentsValid(args map[string]string) bool {
 
-	for _, a := range args {
-		if !isStringValid(a) {
+	for k, a := range args {
+		if k == "" && a == "" {
+			// header without keywords
+			continue
+		}
+		if _, _, err := NormalizeArgument(k, a); err != nil {
 			return false
 		}
 	}
@@ -256,7 +392,7 @@ func (e *Item) isValid() bool {
 	if !isVersionValid(e.Version) {
 		return false
 	}
-	if !isStringValid(e.Target) {
+	if !areTargetsValid(e.Targets()) {
 		return false
 	}
 	if !areArgumentsValid(e.Arguments) {
@@ -269,19 +405,43 @@ func (e *Item) isValid() bool {
 	return true
 }
 
+// isUnchanged returns true if the Item was read from a changelog and
+// none of its fields was changed since then
+func (e *Item) isUnchanged() bool {
+
+	return e.raw != "" && reflect.DeepEqual(e.ChangelogEntry, e.parsed)
+}
+
+// Header returns the first line of an Item, with the source, the
+// version, the target distributions and the keywords
+func (e *Item) Header() (out string) {
+
+	out = fmt.Sprintf("%s (%s) %s;", e.Source, e.Version, strings.Join(e.Targets(), " "))
+	for i, k := range e.Keywords() {
+		if i > 0 {
+			out = out + ","
+		}
+		out = out + " " + k + "=" + e.Arguments[k]
+	}
+
+	return
+}
+
 // String converts an Item variable to a valid string
-// representation ready to be saved in a changelog file
+// representation ready to be saved in a changelog file.
+// Unchanged entries read from a changelog are returned
+// with their original text.
 func (e *Item) String() (out string) {
 
+	if e.isUnchanged() {
+		return e.raw
+	}
+
 	if !e.isValid() {
 		return ""
 	}
 
-	out = fmt.Sprintf("%s (%s) %s;", e.Source, e.Version, e.Target)
-	for k, v := range e.Arguments {
-		out = out + " " + k + "=" + v
-	}
-	out = out + "\n" + e.Changelog
+	out = e.Header() + "\n" + e.Changelog
 	out = out + " -- " + e.ChangedBy + "  " + e.WhenToString()
 	return out + "\n"
 }
@@ -293,35 +453,121 @@ type Items []Item
 // NewItemList returns a slice of Items, reading the contents from a Reader interface
 func NewItemList(reader io.Reader) (Items, error) {
 
-	entries, err := changelog.Parse(reader)
+	data, err := ioutil.ReadAll(reader)
 	if err != nil {
 		return nil, err
 	}
 
-	e := Items{}
-	for _, entry := range entries {
-		e = append(e, Item{entry})
-	}
-
-	return e, nil
+	return newItemListFromBytes(data)
 }
 
 // NewItemList returns a slice of Items, reading the contents a file at the given path
 func NewItemListFromFile(path string) (Items, error) {
 
-	entries, err := changelog.ParseFile(path)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newItemListFromBytes(data)
+}
+
+// newItemListFromBytes parses a changelog, keeping the original text of each entry
+func newItemListFromBytes(data []byte) (Items, error) {
+
+	entries, err := changelog.Parse(bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
 
+	raw := splitEntries(string(data))
+	if len(raw) != len(entries) {
+		raw = nil
+	}
+
 	e := Items{}
-	for _, entry := range entries {
-		e = append(e, Item{entry})
+	for i, entry := range entries {
+		item := Item{ChangelogEntry: entry}
+		if raw != nil {
+			item.raw = raw[i]
+			item.parsed = copyEntry(entry)
+			item.keywords = headerKeywords(raw[i])
+		}
+		e = append(e, item)
 	}
 
 	return e, nil
 }
 
+// splitEntries splits the text of a changelog in the texts of its entries,
+// following the rules of the parser: an entry starts with a line not beginning
+// with a space and ends with the trailer line. The empty lines following an
+// entry, and the ones before the first one, are kept with the entry.
+func splitEntries(text string) (entries []string) {
+
+	var leading string
+	inEntry := false
+	for _, line := range strings.SplitAfter(text, "\n") {
+		switch {
+		case line == "":
+		case inEntry:
+			entries[len(entries)-1] += line
+			if strings.HasPrefix(line, " -- ") {
+				inEntry = false
+			}
+		case line != "\n" && !strings.HasPrefix(line, " "):
+			if len(entries) == 0 {
+				line = leading + line
+			}
+			entries = append(entries, line)
+			inEntry = true
+		case len(entries) == 0:
+			leading += line
+		default:
+			entries[len(entries)-1] += line
+		}
+	}
+
+	return
+}
+
+// headerKeywords returns the keys of the keywords in the header of an
+// entry text, in the order they are written
+func headerKeywords(text string) (keys []string) {
+
+	header := strings.TrimLeft(text, "\n")
+	if i := strings.Index(header, "\n"); i >= 0 {
+		header = header[:i]
+	}
+	i := strings.Index(header, ";")
+	if i < 0 {
+		return nil
+	}
+
+	for _, keyword := range strings.Split(header[i+1:], ",") {
+		key := strings.TrimSpace(strings.SplitN(keyword, "=", 2)[0])
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return
+}
+
+// copyEntry returns a deep copy of a changelog entry
+func copyEntry(entry changelog.ChangelogEntry) changelog.ChangelogEntry {
+
+	out := entry
+	if entry.Arguments != nil {
+		out.Arguments = make(map[string]string, len(entry.Arguments))
+		for k, v := range entry.Arguments {
+			out.Arguments[k] = v
+		}
+	}
+
+	return out
+}
+
 // Source return the Source field of the (chronologically) last Item in the slice
 func (e *Items) Source() string {
 	if e != nil && len(*e) > 0 {
@@ -340,15 +586,24 @@ func (e *Items) Version() version.Version {
 	return version.Version{Epoch: 0, Version: "0.0.0", Revision: "1"}
 }
 
-// Source return the Target field of the (chronologically) last Item in the slice
+// Target return the target distributions, separated by spaces, of the (chronologically) last Item in the slice
 func (e *Items) Target() string {
 	if e != nil && len(*e) > 0 {
-		return (*e)[0].Target
+		return strings.Join((*e)[0].Targets(), " ")
 	}
 
 	return ""
 }
 
+// Targets return the list of target distributions of the (chronologically) last Item in the slice
+func (e *Items) Targets() []string {
+	if e != nil && len(*e) > 0 {
+		return (*e)[0].Targets()
+	}
+
+	return nil
+}
+
 // Source return the Changelog field of the (chronologically) last Item in the slice
 func (e *Items) Changelog() string {
 	if e != nil && len(*e) > 0 {
@@ -381,7 +636,8 @@ func (e *Items) String() string {
 
 	var contents string
 	for _, entry := range *e {
-		if contents != "" {
+		// entries read from a changelog keep their trailing empty lines
+		if contents != "" && !strings.HasSuffix(contents, "\n\n") {
 			contents += "\n"
 		}
 		c := entry.String()
@@ -19,6 +19,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -82,17 +83,67 @@ func TestEntryToString(t *testing.T) {
 				customArg: [2]string{"custom", "value"},
 			},
 			want: []string{
-				"package-name (1.0.0-1) unstable; urgency=low custom=value\n" +
+				"package-name (1.0.0-1) unstable; urgency=low, custom=value\n" +
 					"\n" +
 					"Text\n" +
 					"\n" +
 					" -- My Name <my.name@mail.tld>  " + WhenToString(now) + "\n",
-				"package-name (1.0.0-1) unstable; custom=value urgency=low\n" +
+			},
+		},
+		{
+			name: "entryToString-binary-only-ok",
+			args: args{
+				source:    "package-name",
+				v:         dchversion.NewVersion(0, "1.0.0", "1"),
+				target:    "unstable",
+				urgency:   "High (security)",
+				c:         "Text",
+				author:    "My Name <my.name@mail.tld>",
+				when:      now,
+				customArg: [2]string{"Binary-Only", "YES"},
+			},
+			want: []string{
+				"package-name (1.0.0-1) unstable; urgency=high (security), binary-only=yes\n" +
+					"\n" +
+					"Text\n" +
+					"\n" +
+					" -- My Name <my.name@mail.tld>  " + WhenToString(now) + "\n",
+			},
+		},
+		{
+			name: "entryToString-binary-only-error",
+			args: args{
+				source:    "package-name",
+				v:         dchversion.NewVersion(0, "1.0.0", "1"),
+				target:    "unstable",
+				urgency:   "low",
+				c:         "Text",
+				author:    "My Name <my.name@mail.tld>",
+				when:      now,
+				customArg: [2]string{"binary-only", "no"},
+			},
+			want: []string{
+				"package-name (1.0.0-1) unstable; urgency=low\n" +
 					"\n" +
 					"Text\n" +
 					"\n" +
 					" -- My Name <my.name@mail.tld>  " + WhenToString(now) + "\n",
 			},
+			wantArgError: true,
+		},
+		{
+			name: "entryToString-unknown-urgency-error",
+			args: args{
+				source:  "package-name",
+				v:       dchversion.NewVersion(0, "1.0.0", "1"),
+				target:  "unstable",
+				urgency: "urgent",
+				c:       "Text",
+				author:  "My Name <my.name@mail.tld>",
+				when:    now,
+			},
+			want:      []string{""},
+			wantError: true,
 		},
 		{
 			name: "entryToString-no-source-error",
@@ -700,6 +751,108 @@ func TestTarget(t *testing.T) {
 	}
 }
 
+func TestTargets(t *testing.T) {
+
+	const text = `test (0.0.4-1) bookworm-security bookworm; urgency=high
+
+  * Security fix.
+
+ -- Test Author <test.author@nomail.org>  Wed, 15 Mar 2017 17:34:52 +0000
+`
+
+	tests := []struct {
+		name       string
+		item       func() (Item, error)
+		want       []string
+		wantHeader string
+		wantError  bool
+	}{
+		{
+			name: `parsed`,
+			item: func() (Item, error) {
+				items, err := NewItemList(strings.NewReader(text))
+				if err != nil {
+					return Item{}, err
+				}
+				return items[0], nil
+			},
+			want:       []string{"bookworm-security", "bookworm"},
+			wantHeader: "test (0.0.4-1) bookworm-security bookworm; urgency=high",
+		},
+		{
+			name: `new`,
+			item: func() (Item, error) {
+				return NewItem("test", dchversion.NewVersion(0, "0.0.5", "1"), "stable  stable-security", "low", "  * New entry.", "Test Author <test.author@nomail.org>")
+			},
+			want:       []string{"stable", "stable-security"},
+			wantHeader: "test (0.0.5-1) stable stable-security; urgency=low",
+		},
+		{
+			name: `set`,
+			item: func() (Item, error) {
+				item, err := NewItem("test", dchversion.NewVersion(0, "0.0.5", "1"), "unstable", "low", "  * New entry.", "Test Author <test.author@nomail.org>")
+				if err != nil {
+					return item, err
+				}
+				return item, item.SetTargets("bookworm", "bookworm-backports")
+			},
+			want:       []string{"bookworm", "bookworm-backports"},
+			wantHeader: "test (0.0.5-1) bookworm bookworm-backports; urgency=low",
+		},
+		{
+			name: `assigned`,
+			item: func() (Item, error) {
+				item, err := NewItem("test", dchversion.NewVersion(0, "0.0.5", "1"), "unstable", "low", "  * New entry.", "Test Author <test.author@nomail.org>")
+				if err != nil {
+					return item, err
+				}
+				if err = item.SetTargets("bookworm", "bookworm-backports"); err != nil {
+					return item, err
+				}
+				item.Target = "testing"
+				return item, nil
+			},
+			want:       []string{"testing"},
+			wantHeader: "test (0.0.5-1) testing; urgency=low",
+		},
+		{
+			name: `empty`,
+			item: func() (Item, error) {
+				var item Item
+				return item, item.SetTargets()
+			},
+			wantError: true,
+		},
+		{
+			name: `space`,
+			item: func() (Item, error) {
+				var item Item
+				return item, item.SetTargets("stable", "stable security")
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			item, err := tt.item()
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Targets() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil {
+				return
+			}
+			if got := item.Targets(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Targets() = %v, want %v", got, tt.want)
+			}
+			if got := item.Header(); got != tt.wantHeader {
+				t.Errorf("Header() = %q, want %q", got, tt.wantHeader)
+			}
+		})
+	}
+}
+
 func TestChangelog(t *testing.T) {
 
 	pwd, _ := filepath.Abs(filepath.Dir(os.Args[0]))
@@ -763,3 +916,90 @@ func TestAuthor(t *testing.T) {
 		})
 	}
 }
+
+func TestItemsRoundTrip(t *testing.T) {
+	const (
+		text = `test (0.0.4-1) unstable; urgency=medium, binary-only=yes
+
+  * Second release,   with  odd   spacing.
+
+
+ -- Test Author <test.author@nomail.org>  Wed, 15 Mar 2017 17:34:52 +0000
+
+
+test (0.0.3-1) unstable; urgency=low
+
+  * Initial release.
+
+ -- Test Author <test.author@nomail.org>  Tue, 14 Mar 2017 17:34:52 +0000
+`
+		added = `test (0.0.5-1) unstable; urgency=low
+
+  * New entry.
+
+ -- Test Author <test.author@nomail.org>  Thu, 16 Mar 2017 17:34:52 +0000
+
+`
+	)
+
+	tests := []struct {
+		name   string
+		change func(items Items) Items
+		want   string
+	}{
+		{
+			name:   `unchanged`,
+			change: func(items Items) Items { return items },
+			want:   text,
+		},
+		{
+			name: `prepend`,
+			change: func(items Items) Items {
+				item, _ := NewItem("test", dchversion.NewVersion(0, "0.0.5", "1"), "unstable", "low", "  * New entry.", "Test Author <test.author@nomail.org>")
+				item.When, _ = time.Parse(time.RFC1123Z, "Thu, 16 Mar 2017 17:34:52 +0000")
+				return append(Items{item}, items...)
+			},
+			want: added + text,
+		},
+		{
+			name: `keywordsOrder`,
+			change: func(items Items) Items {
+				items[0].SetUrgency("HIGH (security)")
+				return items[:1]
+			},
+			want: `test (0.0.4-1) unstable; urgency=high (security), binary-only=yes
+
+  * Second release,   with  odd   spacing.
+
+
+ -- Test Author <test.author@nomail.org>  Wed, 15 Mar 2017 17:34:52 +0000
+`,
+		},
+		{
+			name: `modified`,
+			change: func(items Items) Items {
+				items[1].SetUrgency("high")
+				return items
+			},
+			want: text[:strings.Index(text, "test (0.0.3-1)")] + `test (0.0.3-1) unstable; urgency=high
+
+  * Initial release.
+
+ -- Test Author <test.author@nomail.org>  Tue, 14 Mar 2017 17:34:52 +0000
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := NewItemList(strings.NewReader(text))
+			if err != nil {
+				t.Fatalf("cannot read changelog: %s", err)
+			}
+
+			items = tt.change(items)
+			if got := items.String(); got != tt.want {
+				t.Errorf("String() =\n'%v', want\n'%v'", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"errors"
+)
+
+var (
+	// ErrEmptyChangelog is returned when the changelog, or the selected
+	// range of entries, has no entries
+	ErrEmptyChangelog = errors.New("empty changelog")
+	// ErrVersionRegression is returned when a new version is lower than the
+	// version of the previous entry
+	ErrVersionRegression = errors.New("version regression")
+	// ErrInvalidDistribution is returned when a distribution is empty or not valid
+	ErrInvalidDistribution = errors.New("invalid distribution")
+	// ErrInvalidEntry is returned when a field of an entry is empty or not valid
+	ErrInvalidEntry = errors.New("invalid changelog entry")
+	// ErrNoOpenEntry is returned when the entry on top of the changelog is not UNRELEASED
+	ErrNoOpenEntry = errors.New("no UNRELEASED entry")
+	// ErrNoReleases is returned when no releases are found to initialize a changelog
+	ErrNoReleases = errors.New("no releases found")
+)
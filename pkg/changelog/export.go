@@ -0,0 +1,222 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cinello/git-dch/pkg/dchversion"
+)
+
+// ExportEntry is the structured representation of a changelog entry,
+// used by the exporters
+type ExportEntry struct {
+	Source        string   `json:"source"`
+	Version       string   `json:"version"`
+	VersionType   string   `json:"version_type"`
+	Distributions []string `json:"distributions"`
+	Urgency       string   `json:"urgency"`
+	Maintainer    string   `json:"maintainer"`
+	Date          string   `json:"date"`
+	Closes        []string `json:"closes"`
+	Changes       []string `json:"changes"`
+}
+
+// Export returns the structured representation of the entries; if
+// skipSnapshots is true the snapshot and development entries are omitted
+func (e Items) Export(skipSnapshots bool) (entries []ExportEntry) {
+
+	entries = []ExportEntry{}
+	for _, item := range e {
+		v := dchversion.NewVersionFromDebian(item.Version)
+		t := v.Type()
+		if skipSnapshots && (t == dchversion.Snapshot || t == dchversion.Development) {
+			continue
+		}
+
+		entry := ExportEntry{
+			Source:        item.Source,
+			Version:       v.String(),
+			VersionType:   t.String(),
+			Distributions: item.Targets(),
+			Urgency:       item.Arguments["urgency"],
+			Maintainer:    item.ChangedBy,
+			Date:          item.When.Format(time.RFC3339),
+			Closes:        Closes(item.Changelog),
+			Changes:       Changes(item.Changelog),
+		}
+		if entry.Distributions == nil {
+			entry.Distributions = []string{}
+		}
+		if entry.Closes == nil {
+			entry.Closes = []string{}
+		}
+		entries = append(entries, entry)
+	}
+
+	return
+}
+
+// Changes splits the text of an entry in its changes: a change starts with
+// a "*" bullet and includes the following indented lines, which are joined
+// with newlines without the common indentation. The other lines, like the
+// "[ Name ]" lines of the multi maintainer changelogs, are single changes.
+func Changes(text string) (changes []string) {
+
+	changes = []string{}
+	inChange := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			inChange = false
+		case strings.HasPrefix(trimmed, "* "), trimmed == "*":
+			changes = append(changes, strings.TrimSpace(strings.TrimPrefix(trimmed, "*")))
+			inChange = true
+		case inChange && strings.HasPrefix(line, "    "):
+			changes[len(changes)-1] += "\n" + strings.TrimRight(line[4:], " \t")
+		default:
+			changes = append(changes, trimmed)
+			inChange = false
+		}
+	}
+
+	return
+}
+
+// ExportJSON writes the entries as a JSON array
+func ExportJSON(writer io.Writer, entries []ExportEntry) error {
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(entries)
+}
+
+// ExportYAML writes the entries as a YAML sequence. All the strings are
+// double quoted, so no value can be mistaken for a number or a boolean.
+func ExportYAML(writer io.Writer, entries []ExportEntry) error {
+
+	w := bufio.NewWriter(writer)
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "[]")
+	}
+	for _, e := range entries {
+		fmt.Fprintf(w, "- source: %s\n", yamlString(e.Source))
+		fmt.Fprintf(w, "  version: %s\n", yamlString(e.Version))
+		fmt.Fprintf(w, "  version_type: %s\n", yamlString(e.VersionType))
+		writeYAMLList(w, "distributions", e.Distributions)
+		fmt.Fprintf(w, "  urgency: %s\n", yamlString(e.Urgency))
+		fmt.Fprintf(w, "  maintainer: %s\n", yamlString(e.Maintainer))
+		fmt.Fprintf(w, "  date: %s\n", yamlString(e.Date))
+		writeYAMLList(w, "closes", e.Closes)
+		writeYAMLList(w, "changes", e.Changes)
+	}
+
+	return w.Flush()
+}
+
+func writeYAMLList(w io.Writer, key string, values []string) {
+
+	if len(values) == 0 {
+		fmt.Fprintf(w, "  %s: []\n", key)
+		return
+	}
+
+	fmt.Fprintf(w, "  %s:\n", key)
+	for _, v := range values {
+		fmt.Fprintf(w, "    - %s\n", yamlString(v))
+	}
+}
+
+// yamlString returns a YAML double quoted scalar: the escape sequences
+// produced by strconv.Quote are a subset of the YAML ones
+func yamlString(value string) string {
+
+	return strconv.Quote(value)
+}
+
+// ExportMarkdown writes the entries as a Markdown document, with a section
+// for each version and a bullet for each change
+func ExportMarkdown(writer io.Writer, entries []ExportEntry) error {
+
+	w := bufio.NewWriter(writer)
+	if len(entries) > 0 {
+		fmt.Fprintf(w, "# Changelog of %s\n", markdownEscape(entries[0].Source))
+	}
+	for _, e := range entries {
+		date := e.Date
+		if t, err := time.Parse(time.RFC3339, e.Date); err == nil {
+			date = t.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "\n## %s - %s\n\n", markdownEscape(e.Version), date)
+		for _, change := range e.Changes {
+			lines := strings.Split(change, "\n")
+			fmt.Fprintf(w, "- %s\n", markdownEscape(lines[0]))
+			for _, line := range lines[1:] {
+				fmt.Fprintf(w, "  %s\n", markdownEscape(line))
+			}
+		}
+	}
+
+	return w.Flush()
+}
+
+// markdownEscape escapes the characters that would be read as HTML tags
+func markdownEscape(value string) string {
+
+	return strings.NewReplacer(`\`, `\\`, `<`, `\<`, `>`, `\>`).Replace(value)
+}
+
+// ExportRPM writes the entries in the format of the %changelog section of
+// an RPM spec file, with the versions converted by dchversion.Version.RPMString.
+// The percent signs are doubled, so they are not expanded as macros.
+func ExportRPM(writer io.Writer, entries []ExportEntry) error {
+
+	w := bufio.NewWriter(writer)
+	escape := strings.NewReplacer("%", "%%")
+	for i, e := range entries {
+		v, err := dchversion.Parse(e.Version)
+		if err != nil {
+			return err
+		}
+		var t time.Time
+		if t, err = time.Parse(time.RFC3339, e.Date); err != nil {
+			return err
+		}
+
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "* %s %s - %s\n", t.Format("Mon Jan 02 2006"), escape.Replace(e.Maintainer), v.RPMString())
+		for _, change := range e.Changes {
+			lines := strings.Split(change, "\n")
+			fmt.Fprintf(w, "- %s\n", escape.Replace(lines[0]))
+			for _, line := range lines[1:] {
+				fmt.Fprintf(w, "  %s\n", escape.Replace(line))
+			}
+		}
+	}
+
+	return w.Flush()
+}
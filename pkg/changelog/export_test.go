@@ -0,0 +1,133 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const exportChangelog = `pkg (1.2.0.20180214-1) unstable; urgency=low
+
+  * Snapshot change
+
+ -- A B <a@b.c>  Wed, 14 Feb 2018 10:00:00 +0000
+
+pkg (1.1-1) unstable; urgency=medium
+
+  [ C D ]
+  * Second change,
+    on two lines. Closes: #200
+  * Use <b> tags
+
+ -- C D <c@d.e>  Tue, 13 Feb 2018 10:00:00 +0000
+`
+
+func TestChanges(t *testing.T) {
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: `empty`, text: "\n\n", want: []string{}},
+		{name: `bullets`, text: "\n  * One\n  * Two\n    - nested\n\n", want: []string{"One", "Two\n- nested"}},
+		{name: `maintainers`, text: "\n  [ A B ]\n  * One\n\n  [ C D ]\n  * Two\n", want: []string{"[ A B ]", "One", "[ C D ]", "Two"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Changes(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Changes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+
+	items, err := NewItemList(strings.NewReader(exportChangelog))
+	if err != nil {
+		t.Fatalf("NewItemList() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		export        func(io.Writer, []ExportEntry) error
+		skipSnapshots bool
+		want          string
+	}{
+		{
+			name:          `yaml`,
+			export:        ExportYAML,
+			skipSnapshots: true,
+			want: "- source: \"pkg\"\n  version: \"1.1-1\"\n  version_type: \"release\"\n  distributions:\n    - \"unstable\"\n" +
+				"  urgency: \"medium\"\n  maintainer: \"C D <c@d.e>\"\n  date: \"2018-02-13T10:00:00Z\"\n  closes:\n    - \"200\"\n" +
+				"  changes:\n    - \"[ C D ]\"\n    - \"Second change,\\non two lines. Closes: #200\"\n    - \"Use <b> tags\"\n",
+		},
+		{
+			name:   `markdown`,
+			export: ExportMarkdown,
+			want: "# Changelog of pkg\n\n## 1.2.0.20180214-1 - 2018-02-14\n\n- Snapshot change\n\n## 1.1-1 - 2018-02-13\n\n" +
+				"- [ C D ]\n- Second change,\n  on two lines. Closes: #200\n- Use \\<b\\> tags\n",
+		},
+		{
+			name:   `rpm`,
+			export: ExportRPM,
+			want: "* Wed Feb 14 2018 A B <a@b.c> - 1.2.0.20180214-1\n- Snapshot change\n\n" +
+				"* Tue Feb 13 2018 C D <c@d.e> - 1.1-1\n- [ C D ]\n- Second change,\n  on two lines. Closes: #200\n- Use <b> tags\n",
+		},
+		{
+			name:   `json`,
+			export: ExportJSON,
+			want: `[
+  {
+    "source": "pkg",
+    "version": "1.2.0.20180214-1",
+    "version_type": "development",
+    "distributions": [
+      "unstable"
+    ],
+    "urgency": "low",
+    "maintainer": "A B <a@b.c>",
+    "date": "2018-02-14T10:00:00Z",
+    "closes": [],
+    "changes": [
+      "Snapshot change"
+    ]
+  }
+]
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := items.Export(tt.skipSnapshots)
+			if tt.name == `json` {
+				entries = entries[:1]
+			}
+			var b bytes.Buffer
+			if err := tt.export(&b, entries); err != nil {
+				t.Fatalf("export error = %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("export = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
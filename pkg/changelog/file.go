@@ -18,13 +18,19 @@ package changelog
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/cinello/git-dch/internal/errkind"
 	"github.com/cinello/git-dch/pkg/dchversion"
 	"github.com/cinello/git-dch/pkg/git"
+	"github.com/cinello/git-dch/pkg/logging"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 )
 
 var (
@@ -33,7 +39,22 @@ var (
 
 // File struct contains all the entries of a changelog
 type File struct {
-	el Items
+	el     Items
+	filter git.Filter
+
+	squashCherryPicks bool
+	dropReverts       bool
+	wrapWidth         int
+	strictSince       bool
+
+	backupSuffix    string
+	mergeUnreleased bool
+
+	snapshotPolicy HistoryPolicy
+	testingPolicy  HistoryPolicy
+	unstablePolicy HistoryPolicy
+
+	logger *logging.Logger
 }
 
 // New function create a new File struct reading the contents from a Reader interface
@@ -58,6 +79,64 @@ func NewFromFile(path string) (*File, error) {
 	return &File{el: entries}, nil
 }
 
+// SetFilter sets the rules used to select the commits added to the changelog
+func (f *File) SetFilter(filter git.Filter) {
+
+	f.filter = filter
+}
+
+// SetNetChanges configures the removal of cherry-picked duplicates and of
+// reverted commits from the changes added to the changelog
+func (f *File) SetNetChanges(squashCherryPicks, dropReverts bool) {
+
+	f.squashCherryPicks = squashCherryPicks
+	f.dropReverts = dropReverts
+}
+
+// SetWrapWidth sets the width where the lines of the changes added to the
+// changelog are wrapped, zero disables the wrapping
+func (f *File) SetWrapWidth(width int) {
+
+	f.wrapWidth = width
+}
+
+// SetBackupSuffix sets the suffix of the copy of the previous contents kept
+// by WriteToFile, an empty suffix disables the copy
+func (f *File) SetBackupSuffix(suffix string) {
+
+	f.backupSuffix = suffix
+}
+
+// SetStrictSince makes the changelog generation fail when the commit given
+// as since does not exist or is not an ancestor of HEAD, instead of listing
+// the whole history
+func (f *File) SetStrictSince(strict bool) {
+
+	f.strictSince = strict
+}
+
+// SetLogger sets the logger explaining how the commits and the versions of
+// the new entries are chosen, nil disables the logging
+func (f *File) SetLogger(l *logging.Logger) {
+
+	f.logger = l
+}
+
+// repository opens the git repository in the current directory, configured
+// with the File settings
+func (f *File) repository() (gr git.Repository, err error) {
+
+	if gr, err = git.NewRepositoryFromCurrentDirectory(); err != nil {
+		return
+	}
+	gr.SetFilter(f.filter)
+	gr.SetNetChanges(f.squashCherryPicks, f.dropReverts)
+	gr.SetWrapWidth(f.wrapWidth)
+	gr.SetLogger(f.logger)
+
+	return
+}
+
 func (f *File) computeNewVersion(v dchversion.Version) (newVersion dchversion.Version, err error) {
 
 	newVersion = v
@@ -87,10 +166,12 @@ func (f *File) computeNewVersion(v dchversion.Version) (newVersion dchversion.Ve
 		oldNative := old.ExtractNative()
 		newNative := newVersion.ExtractNative()
 		if dchversion.Compare(newNative, oldNative) < 0 {
-			err = fmt.Errorf("the new version %s is lesser than the old version %s", v.String(), old.String())
+			err = errkind.New(ErrVersionRegression, "the new version %s is lesser than the old version %s", v.String(), old.String())
 		}
 		if dchversion.Compare(newNative, oldNative) == 0 {
 			newVersion, err = old.IncrementRevision()
+			f.logger.Info("revision bumped, the upstream version is the same of the last entry",
+				logging.F("old", old.String()), logging.F("new", newVersion.String()))
 		}
 		return
 	}
@@ -112,6 +193,8 @@ func (f *File) computeNewVersion(v dchversion.Version) (newVersion dchversion.Ve
 		}
 		if c == 0 {
 			newVersion, err = v.IncrementRevision()
+			f.logger.Info("snapshot number bumped, the snapshot is based on the same version of the last entry",
+				logging.F("old", old.String()), logging.F("new", newVersion.String()))
 		}
 		return
 	}
@@ -120,10 +203,12 @@ func (f *File) computeNewVersion(v dchversion.Version) (newVersion dchversion.Ve
 
 	// if newVersion is not native, then it's the target version
 	if dchversion.Compare(v, old) < 0 {
-		err = fmt.Errorf("the new version %s is lesser than the old version %s", v.String(), old.String())
+		err = errkind.New(ErrVersionRegression, "the new version %s is lesser than the old version %s", v.String(), old.String())
 	}
 	if dchversion.Compare(v, old) == 0 {
 		newVersion, err = v.IncrementRevision()
+		f.logger.Info("revision bumped, the version is the same of the last entry",
+			logging.F("old", old.String()), logging.F("new", newVersion.String()))
 	}
 	return
 }
@@ -147,11 +232,11 @@ func (f *File) computeSourceName(source string) (out string, err error) {
 	}
 
 	if strings.Contains(out, " ") {
-		return out, fmt.Errorf("source contains spaces")
+		return out, errkind.New(ErrInvalidEntry, "source contains spaces")
 	}
 
 	if out == "" {
-		return out, fmt.Errorf("target is an empty string")
+		return out, errkind.New(ErrInvalidEntry, "source is an empty string")
 	}
 
 	return out, err
@@ -159,17 +244,14 @@ func (f *File) computeSourceName(source string) (out string, err error) {
 
 func (f *File) computeTargetName(target string) (out string, err error) {
 
-	out = target
+	// the target can be a list of distributions separated by spaces
+	out = strings.Join(strings.Fields(target), " ")
 	if out == "" && !f.IsEmpty() {
 		out = f.el.Target()
 	}
 
-	if strings.Contains(out, " ") {
-		return out, fmt.Errorf("target contains spaces")
-	}
-
 	if out == "" {
-		return out, fmt.Errorf("target is an empty string")
+		return out, errkind.New(ErrInvalidDistribution, "target is an empty string")
 	}
 
 	return
@@ -183,7 +265,7 @@ func (f *File) computeAuthor(author string) (out string, err error) {
 	}
 
 	if out == "" {
-		return out, fmt.Errorf("target is an empty string")
+		return out, errkind.New(ErrInvalidEntry, "author is an empty string")
 	}
 
 	return
@@ -205,7 +287,7 @@ func (f *File) Len() int {
 func (f *File) LastVersion() (v dchversion.Version, err error) {
 
 	if f.IsEmpty() {
-		err = fmt.Errorf("the changelog file is empty, cannot get last release version")
+		err = errkind.New(ErrEmptyChangelog, "the changelog file is empty, cannot get last release version")
 		return
 	}
 
@@ -213,6 +295,45 @@ func (f *File) LastVersion() (v dchversion.Version, err error) {
 	return
 }
 
+// Summary function return the description of the entries selected by
+// Items.Range, with the same fields printed by dpkg-parsechangelog
+func (f *File) Summary(count int, since, until string) (s Summary, err error) {
+
+	if f.IsEmpty() {
+		err = errkind.New(ErrEmptyChangelog, "the changelog file is empty, cannot get its summary")
+		return
+	}
+
+	var items Items
+	if items, err = f.el.Range(count, since, until); err != nil {
+		return
+	}
+	if len(items) == 0 {
+		err = errkind.New(ErrEmptyChangelog, "no changelog entries in the selected range")
+		return
+	}
+
+	return items.Summary(), nil
+}
+
+// Export function return the structured representation of the entries in the
+// changelog, optionally without the snapshot and development ones
+func (f *File) Export(skipSnapshots bool) []ExportEntry {
+
+	return f.el.Export(skipSnapshots)
+}
+
+// SetArgument function create or change a keyword in the header of the
+// (chronologically) last entry in the changelog
+func (f *File) SetArgument(key, value string) error {
+
+	if f.IsEmpty() {
+		return errkind.New(ErrEmptyChangelog, "the changelog file is empty, cannot set argument %s", key)
+	}
+
+	return f.el[0].SetArgument(key, value)
+}
+
 // Write function write all the contents of the File slice to a Writer interface
 func (f *File) Write(writer io.Writer) (int, error) {
 
@@ -220,12 +341,72 @@ func (f *File) Write(writer io.Writer) (int, error) {
 	return writer.Write([]byte(text))
 }
 
-// WriteToFile function save all the changes of the File slice into a text file at the given path
+// WriteToFile function save all the changes of the File slice into a text file at the given path.
+// The contents are written to a temporary file in the same directory, synced and renamed over the
+// file, so that a failure never leaves a truncated changelog; the permissions of the existing file
+// are kept and, when a backup suffix is set, its previous contents are copied beside it.
 func (f *File) WriteToFile(path string) (n int, err error) {
 
+	// write through symbolic links instead of replacing them
+	if target, linkErr := filepath.EvalSymlinks(path); linkErr == nil {
+		path = target
+	}
+
+	mode := os.FileMode(0644)
+	info, statErr := os.Stat(path)
+	if statErr == nil {
+		mode = info.Mode().Perm()
+	}
+
 	var file *os.File
+	if file, err = ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp"); err != nil {
+		return
+	}
 
-	if file, err = os.Create(path); err != nil {
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	if n, err = file.WriteString(f.el.String()); err != nil {
+		return
+	}
+	if err = file.Chmod(mode); err != nil {
+		return
+	}
+	if err = file.Sync(); err != nil {
+		return
+	}
+	if err = file.Close(); err != nil {
+		return
+	}
+
+	if statErr == nil && f.backupSuffix != "" {
+		if err = copyFile(path, path+f.backupSuffix, mode); err != nil {
+			return
+		}
+	}
+
+	if err = os.Rename(file.Name(), path); err != nil {
+		return
+	}
+	syncDirectory(filepath.Dir(path))
+
+	return
+}
+
+// copyFile copies the contents of a file to a new file with the given permissions
+func copyFile(source, destination string, mode os.FileMode) (err error) {
+
+	var data []byte
+	if data, err = ioutil.ReadFile(source); err != nil {
+		return
+	}
+
+	var file *os.File
+	if file, err = os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode); err != nil {
 		return
 	}
 
@@ -235,9 +416,23 @@ func (f *File) WriteToFile(path string) (n int, err error) {
 		}
 	}()
 
-	n, err = file.WriteString(f.el.String())
+	if _, err = file.Write(data); err != nil {
+		return
+	}
 
-	return
+	return file.Sync()
+}
+
+// syncDirectory flushes a directory, making a rename in it durable; the errors
+// are ignored, as not every platform allows syncing a directory
+func syncDirectory(path string) {
+
+	dir, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	_ = dir.Sync()
+	_ = dir.Close()
 }
 
 func (f *File) addSimple(
@@ -265,7 +460,9 @@ func (f *File) addSimple(
 		return
 	}
 
-	entry, err = NewItem(s, v, t, urgency, clog, a)
+	if entry, err = NewItem(s, v, t, urgency, clog, a); err != nil {
+		return
+	}
 
 	f.el = append(Items{entry}, f.el...)
 
@@ -275,24 +472,53 @@ func (f *File) addSimple(
 func (f *File) getLog(since string, auto bool, ignoreMerges bool) (out string, err error) {
 
 	var gr git.Repository
-	if gr, err = git.NewRepositoryFromCurrentDirectory(); err != nil {
+	if gr, err = f.repository(); err != nil {
 		return
 	}
 
+	var list []*object.Commit
+	if list, err = f.getCommits(gr, since, auto, ignoreMerges); err != nil {
+		return
+	}
+
+	return gr.LogCommits(list, false, false, true, false), nil
+}
+
+// getCommits returns the commits to add to a new entry of the changelog
+func (f *File) getCommits(gr git.Repository, since string, auto bool, ignoreMerges bool) (list []*object.Commit, err error) {
+
+	if since != "" && f.strictSince {
+		f.logger.Info("changes listed from the given commit", logging.F("since", since))
+		return gr.CommitsToCommitStrict(since, ignoreMerges)
+	}
 	if since != "" {
-		return gr.LogToCommit(since, false, false, true, false, ignoreMerges)
+		f.logger.Info("changes listed from the given commit", logging.F("since", since), logging.F("lenient", true))
+		return gr.CommitsToCommit(since, ignoreMerges)
 	}
 
 	if !f.IsEmpty() && auto {
-		// 1) The start commit is read from the snapshot banner (see below for details)
+		// 1) The start commit is read from the snapshot banner, or from the hash in the snapshot version
 		v := dchversion.NewVersionFromDebian(f.el.Version())
 		if v.Type() == dchversion.Snapshot {
 			values := regExSnapshot.FindAllStringSubmatch(f.el.Changelog(), -1)
-			if len(values) != 1 || len(values[0]) != 2 {
-				err = fmt.Errorf("cannot find valit commit hash in the last snapshot args")
+			if len(values) == 1 && len(values[0]) == 2 {
+				f.logger.Info("changes listed from the commit in the snapshot banner",
+					logging.F("version", v.String()), logging.F("commit", values[0][1]))
+				return gr.CommitsToCommit(values[0][1], ignoreMerges)
+			}
+
+			// without the banner, the version has the abbreviated hash of the snapshot commit
+			var hash string
+			if hash, err = dchversion.GetSnapshotHash(v); err != nil {
 				return
 			}
-			return gr.LogToCommit(values[0][1], false, false, true, false, ignoreMerges)
+			if hash, err = gr.ResolveHash(hash); err != nil {
+				err = fmt.Errorf("cannot find the commit of the snapshot %s: %w", v.String(), err)
+				return
+			}
+			f.logger.Info("changes listed from the commit in the snapshot version",
+				logging.F("version", v.String()), logging.F("commit", hash))
+			return gr.CommitsToCommit(hash, ignoreMerges)
 		}
 
 		// 2) If the topmost version of the debian/changelog is already tagged. Use the commit the tag points to as start commit.
@@ -301,15 +527,20 @@ func (f *File) getLog(since string, auto bool, ignoreMerges bool) (out string, e
 			commit = gr.CommitAtTag(f.el.Version())
 		}
 		if commit != "" {
-			return gr.LogToCommit(commit, false, false, true, false, ignoreMerges)
+			f.logger.Info("changes listed from the tag of the last entry",
+				logging.F("version", f.el.Version().String()), logging.F("commit", commit))
+			return gr.CommitsToCommit(commit, ignoreMerges)
 		}
 
 		// 3) the last git commit after the last changelog release is used as start commit.
-		return gr.LogToTime(f.el.When(), false, false, true, false, ignoreMerges)
+		f.logger.Info("changes listed from the date of the last entry, its version is not tagged",
+			logging.F("version", f.el.Version().String()), logging.F("date", f.el.When().Format(time.RFC3339)))
+		return gr.CommitsToTime(f.el.When(), ignoreMerges)
 	}
 
 	// 4) get all the entries
-	return gr.Log(false, false, true, false, ignoreMerges)
+	f.logger.Info("changes listed from the whole history")
+	return gr.CommitsToCommit("", ignoreMerges)
 }
 
 func (f *File) buildReleaseLog(since string, ver dchversion.Version, auto, ignoreMerges bool) (out string, err error) {
@@ -330,7 +561,7 @@ func (f *File) buildReleaseLog(since string, ver dchversion.Version, auto, ignor
 func (f *File) buildSnapshotLog(since string, auto, ignoreMerges bool) (out string, err error) {
 
 	var gr git.Repository
-	if gr, err = git.NewRepositoryFromCurrentDirectory(); err != nil {
+	if gr, err = f.repository(); err != nil {
 		return
 	}
 	var hash string
@@ -349,29 +580,6 @@ func (f *File) buildSnapshotLog(since string, auto, ignoreMerges bool) (out stri
 	return
 }
 
-func (f *File) purgeReleases(testing, unstable bool) {
-	for i := 0; i < len(f.el); i++ {
-		v := dchversion.NewVersionFromDebian(f.el.Version())
-		isTesting := v.IsStaging() && testing
-		isUnstable := v.IsDevelopment() && unstable
-		if isTesting || isUnstable {
-			f.el = append(f.el[:i], f.el[i+1:]...)
-			i-- // Since we just deleted f.entries[i], we must redo that index
-		}
-	}
-}
-
-func (f *File) purgeSnapshotReleases() {
-
-	for i := 0; i < len(f.el); i++ {
-		v := dchversion.NewVersionFromDebian(f.el.Version())
-		if v.IsSnapshot() {
-			f.el = append(f.el[:i], f.el[i+1:]...)
-			i-- // Since we just deleted f.entries[i], we must redo that index
-		}
-	}
-}
-
 // AddSnapshot function create a new snapshot changelog entry in the File ChangelogEntries slice
 // This function accept the following parameters:
 // since string:      the reference to the commit where the log must start from
@@ -390,11 +598,11 @@ func (f *File) AddSnapshot(
 	case ver.IsStaging():
 		fallthrough
 	case ver.IsDevelopment():
-		err = fmt.Errorf("cannot use value %s as snapshot version", ver.String())
+		err = errkind.New(dchversion.ErrIncompatibleVersion, "cannot use value %s as snapshot version", ver.String())
 		return
 	default:
 		if ver, err = ver.Build(dchversion.Snapshot); err != nil {
-			err = fmt.Errorf("cannot create a snapshot version from value %s: %s", ver.String(), err)
+			err = fmt.Errorf("cannot create a snapshot version from value %s: %w", ver.String(), err)
 			return
 		}
 	}
@@ -404,17 +612,18 @@ func (f *File) AddSnapshot(
 		return
 	}
 
-	return f.addSimple(source, ver, "low", "UNRELEASED", clog, author)
+	return f.addSimple(source, ver, "low", unreleasedTarget, clog, author)
 }
 
 // AddRelease function create a new release changelog entry in the File ChangelogEntries slice
-// The function always purge all the snapshots entries in the slice before add the new one
+// The snapshot entries since the last release are purged before adding the new one, unless
+// SetHistoryPolicies configures to keep them or to squash their changes into the new entry
 // This function accept the following parameters:
 // since string:       the reference to the commit where the log must start from
 // source string:      the name of the package (if empty is guessed from the previous entries)
 // ver Version:        the version number for the new release
 // urgency string:     the urgency identifier for the new release (is empty is set to medium)
-// target string:      the distribution identifier for the new release (if empty is guessed from the previous entries)
+// target string:      the distribution identifiers, separated by spaces, for the new release (if empty is guessed from the previous entries)
 // author string:      the author name/email for the new package (if empty is guessed from the previous entries)
 // ignoreMerges bool:  if true, all the merge commits are omitted from the changelog
 // purgeTesting bool:  if true, all the testing entries are purged from the changelog before the new one is added
@@ -426,27 +635,47 @@ func (f *File) AddRelease(
 	auto, ignoreMerges, purgeTesting, purgeUnstable bool,
 ) (v dchversion.Version, entry Item, err error) {
 
+	// every target must be unstable for a staging release, or none of them
+	targets := strings.Fields(target)
+	unstable := 0
+	for _, t := range targets {
+		if t == "unstable" {
+			unstable++
+		}
+	}
+	if unstable > 0 && unstable < len(targets) {
+		err = errkind.New(ErrInvalidDistribution, "the distributions %s mix unstable with other distributions", strings.Join(targets, " "))
+		return
+	}
+
 	if ver.IsNative() {
 		releaseType := dchversion.Release
-		if target == "unstable" {
+		if unstable > 0 {
 			releaseType = dchversion.Staging
 		}
 
 		if ver, err = ver.Build(releaseType); err != nil {
-			err = fmt.Errorf("cannot create a %s version from value %s: %s", releaseType.SourceBranch(), ver.String(), err)
+			err = fmt.Errorf("cannot create a %s version from value %s: %w", releaseType.SourceBranch(), ver.String(), err)
 			return
 		}
 	}
 
-	f.purgeReleases(purgeTesting, purgeUnstable)
-	f.purgeSnapshotReleases()
+	// the entries are changed on a copy, so that a failure leaves them untouched
+	work := *f
+	squashed := work.applyHistoryPolicies(true, purgeTesting, purgeUnstable)
 
 	var clog string
-	if clog, err = f.buildReleaseLog(since, ver, auto, ignoreMerges); err != nil {
+	if clog, err = work.buildReleaseLog(since, ver, auto, ignoreMerges); err != nil {
 		return
 	}
+	clog = squashChanges(clog, squashed)
 
-	return f.addSimple(source, ver, urgency, target, clog, author)
+	if v, entry, err = work.addSimple(source, ver, urgency, target, clog, author); err != nil {
+		return
+	}
+	f.el = work.el
+
+	return
 }
 
 // Add function create a new generic changelog entry in the File ChangelogEntries slice
@@ -455,11 +684,12 @@ func (f *File) AddRelease(
 // source string:      the name of the package (if empty is guessed from the previous entries)
 // ver Version:        the version number for the new release
 // urgency string:     the urgency identifier for the new release (is empty is set to medium)
-// target string:      the distribution identifier for the new release (if empty is guessed from the previous entries)
+// target string:      the distribution identifiers, separated by spaces, for the new release (if empty is guessed from the previous entries)
 // author string:      the author name/email for the new package (if empty is guessed from the previous entries)
 // ignoreMerges bool:  if true, all the merge commits are omitted from the changelog
 // purgeTesting bool:  if true, all the testing entries are purged from the changelog before the new one is added
 // purgeUnstable bool: if true, all the unstable entries are purged from the changelog before the new one is added
+// When SetMergeUnreleased is enabled and the entry on top is UNRELEASED, the new changes are added to it instead.
 func (f *File) Add(
 	since, source string,
 	ver dchversion.Version,
@@ -467,12 +697,24 @@ func (f *File) Add(
 	auto, ignoreMerges, purgeTesting, purgeUnstable bool,
 ) (v dchversion.Version, c Item, err error) {
 
-	f.purgeReleases(purgeTesting, purgeUnstable)
+	if f.mergeUnreleased && f.HasOpenEntry() {
+		return f.addToOpenEntry(since, ver, auto, ignoreMerges)
+	}
+
+	// the entries are changed on a copy, so that a failure leaves them untouched
+	work := *f
+	squashed := work.applyHistoryPolicies(false, purgeTesting, purgeUnstable)
 
 	var clog string
-	if clog, err = f.getLog(since, auto, ignoreMerges); err != nil {
+	if clog, err = work.getLog(since, auto, ignoreMerges); err != nil {
 		return
 	}
+	clog = squashChanges(clog, squashed)
 
-	return f.addSimple(source, ver, urgency, target, clog, author)
+	if v, c, err = work.addSimple(source, ver, urgency, target, clog, author); err != nil {
+		return
+	}
+	f.el = work.el
+
+	return
 }
@@ -17,7 +17,9 @@ package changelog
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -197,6 +199,87 @@ func TestWrite(t *testing.T) {
 	}
 }
 
+func TestWriteToFile(t *testing.T) {
+	const (
+		changelog01 = `test (0.0.3-1) unstable; urgency=medium
+
+  * Initial release.
+
+ -- Test Author <test.author@nomail.org>  Tue, 14 Mar 2017 17:34:52 +0000
+`
+		previous = "previous contents\n"
+	)
+
+	tests := []struct {
+		name       string
+		existing   bool
+		mode       os.FileMode
+		backup     string
+		wantMode   os.FileMode
+		wantBackup bool
+	}{
+		{name: `new`, wantMode: 0644},
+		{name: `keepMode`, existing: true, mode: 0600, wantMode: 0600},
+		{name: `backup`, existing: true, mode: 0640, backup: "~", wantMode: 0640, wantBackup: true},
+		{name: `backupNew`, backup: "~", wantMode: 0644},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			dir, err := ioutil.TempDir("", "git-dch-test")
+			if err != nil {
+				t.Fatalf("cannot create directory: %s", err)
+			}
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "changelog")
+			if tt.existing {
+				if err = ioutil.WriteFile(path, []byte(previous), tt.mode); err != nil {
+					t.Fatalf("cannot write changelog: %s", err)
+				}
+				if err = os.Chmod(path, tt.mode); err != nil {
+					t.Fatalf("cannot change mode: %s", err)
+				}
+			}
+
+			f, err := New(strings.NewReader(changelog01))
+			if err != nil {
+				t.Fatalf("cannot read changelog: %s", err)
+			}
+			f.SetBackupSuffix(tt.backup)
+			if _, err = f.WriteToFile(path); err != nil {
+				t.Fatalf("WriteToFile() error = %v", err)
+			}
+
+			got, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("cannot read written changelog: %s", err)
+			}
+			if string(got) != changelog01 {
+				t.Errorf("WriteToFile() =\n'%v', want\n'%v'", string(got), changelog01)
+			}
+			if info, _ := os.Stat(path); info.Mode().Perm() != tt.wantMode {
+				t.Errorf("WriteToFile() mode = %v, want %v", info.Mode().Perm(), tt.wantMode)
+			}
+
+			backup, err := ioutil.ReadFile(path + tt.backup)
+			if tt.wantBackup && (err != nil || string(backup) != previous) {
+				t.Errorf("WriteToFile() backup = '%v' (%v), want '%v'", string(backup), err, previous)
+			}
+			if !tt.wantBackup && tt.backup != "" && err == nil {
+				t.Errorf("WriteToFile() wrote an unexpected backup")
+			}
+
+			files, _ := ioutil.ReadDir(dir)
+			for _, file := range files {
+				if strings.HasPrefix(file.Name(), ".") {
+					t.Errorf("WriteToFile() left the temporary file %s", file.Name())
+				}
+			}
+		})
+	}
+}
+
 func TestComputeNewVersion(t *testing.T) {
 
 	pwd, _ := filepath.Abs(filepath.Dir(os.Args[0]))
@@ -570,6 +653,18 @@ func TestAddSimple(t *testing.T) {
 				When:      time.Now(),
 			},
 		},
+		{
+			name: `invalidUrgency`,
+			args: args{
+				r:       strings.NewReader(textSingleEntry),
+				v:       dchversion.NewVersion(0, "1.0.0", "1"),
+				urgency: "whenever",
+				target:  "unstable",
+				changes: "test args",
+				author:  "Test Author <test.author@nomail.org>",
+			},
+			wantError: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -577,8 +672,18 @@ func TestAddSimple(t *testing.T) {
 			if err != nil {
 				t.Errorf("cannot read changelog: %s", err)
 			}
+			entries := file.Len()
 
 			_, got, err := file.addSimple(tt.args.source, tt.args.v, tt.args.urgency, tt.args.target, tt.args.changes, tt.args.author)
+			if tt.wantError {
+				if err == nil {
+					t.Error("expected an error, got nothing")
+				}
+				if file.Len() != entries {
+					t.Errorf("addSimple() added an entry on error")
+				}
+				return
+			}
 			if err != nil {
 				t.Errorf("cannot add args to changelog: %s", err)
 			}
@@ -830,12 +935,12 @@ func TestComputeTargetName(t *testing.T) {
 			want: "unstable",
 		},
 		{
-			name: `error`,
+			name: `multiple`,
 			args: args{
 				r:      strings.NewReader(""),
-				target: "wrong value",
+				target: " bookworm-security  bookworm ",
 			},
-			wantError: true,
+			want: "bookworm-security bookworm",
 		},
 		{
 			name: `test`,
@@ -873,6 +978,38 @@ func TestComputeTargetName(t *testing.T) {
 	}
 }
 
+func TestAddReleaseTargets(t *testing.T) {
+	const (
+		textSingleEntry = `test (0.0.3-1) unstable; urgency=medium
+
+  * Initial release.
+
+ -- Test Author <test.author@nomail.org>  Tue, 14 Mar 2017 17:34:52 +0000
+`
+	)
+
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{name: `unstableFirst`, target: "unstable stable"},
+		{name: `unstableLast`, target: "stable stable-security unstable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(strings.NewReader(textSingleEntry))
+			if err != nil {
+				t.Fatalf("cannot read changelog: %s", err)
+			}
+
+			_, _, err = f.AddRelease("", "", dchversion.NewVersion(0, "0.0.4", ""), "", tt.target, "", false, false, false, false)
+			if !errors.Is(err, ErrInvalidDistribution) {
+				t.Errorf("AddRelease(%s) error = %v, want %v", tt.target, err, ErrInvalidDistribution)
+			}
+		})
+	}
+}
+
 func TestComputeAuthor(t *testing.T) {
 	const (
 		textSingleEntry = `test (0.0.3-1) unstable; urgency=medium
@@ -0,0 +1,168 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cinello/git-dch/pkg/dchversion"
+	"github.com/cinello/git-dch/pkg/logging"
+)
+
+// HistoryPolicy is what a new entry does with the snapshot, testing or
+// unstable entries added on top of the changelog since the last release
+type HistoryPolicy int
+
+const (
+	// DefaultHistory purges the snapshot entries on a release and keeps
+	// the other entries
+	DefaultHistory HistoryPolicy = iota
+	// KeepHistory leaves the entries in the changelog
+	KeepHistory
+	// SquashHistory moves the changes of the entries into the new one,
+	// skipping the changes already listed
+	SquashHistory
+	// PurgeHistory removes the entries from the changelog
+	PurgeHistory
+)
+
+// ParseHistoryPolicy returns the HistoryPolicy with the given name: keep,
+// squash or purge
+func ParseHistoryPolicy(value string) (HistoryPolicy, error) {
+
+	switch strings.ToLower(value) {
+	case "":
+		return DefaultHistory, nil
+	case "keep":
+		return KeepHistory, nil
+	case "squash":
+		return SquashHistory, nil
+	case "purge":
+		return PurgeHistory, nil
+	}
+
+	return DefaultHistory, fmt.Errorf("the history policy %s is not valid", value)
+}
+
+// String returns the name of the HistoryPolicy
+func (p HistoryPolicy) String() string {
+
+	switch p {
+	case KeepHistory:
+		return "keep"
+	case SquashHistory:
+		return "squash"
+	case PurgeHistory:
+		return "purge"
+	}
+
+	return "default"
+}
+
+// SetHistoryPolicies sets what the new entries do with the snapshot, testing
+// and unstable entries added since the last release. The snapshot policy is
+// used only by AddRelease, the purgeTesting and purgeUnstable arguments of
+// Add and AddRelease override the other two.
+func (f *File) SetHistoryPolicies(snapshot, testing, unstable HistoryPolicy) {
+
+	f.snapshotPolicy = snapshot
+	f.testingPolicy = testing
+	f.unstablePolicy = unstable
+}
+
+// historyPolicy returns the policy for an entry with the given version
+func (f *File) historyPolicy(v dchversion.Version, release, purgeTesting, purgeUnstable bool) HistoryPolicy {
+
+	policy := KeepHistory
+	switch {
+	case v.IsSnapshot():
+		if !release {
+			return KeepHistory
+		}
+		policy = PurgeHistory
+		if f.snapshotPolicy != DefaultHistory {
+			policy = f.snapshotPolicy
+		}
+	case v.IsStaging():
+		if purgeTesting {
+			return PurgeHistory
+		}
+		if f.testingPolicy != DefaultHistory {
+			policy = f.testingPolicy
+		}
+	case v.IsDevelopment():
+		if purgeUnstable {
+			return PurgeHistory
+		}
+		if f.unstablePolicy != DefaultHistory {
+			policy = f.unstablePolicy
+		}
+	}
+
+	return policy
+}
+
+// applyHistoryPolicies handles the snapshot, testing and unstable entries on
+// top of the changelog, up to the last stable release, as configured by the
+// policies: the purged and squashed entries are removed, and the squashed ones
+// are returned, from the newest to the oldest.
+func (f *File) applyHistoryPolicies(release, purgeTesting, purgeUnstable bool) (squashed Items) {
+
+	var kept Items
+	i := 0
+	for ; i < len(f.el); i++ {
+		v := dchversion.NewVersionFromDebian(f.el[i].Version)
+		if !v.IsSnapshot() && !v.IsStaging() && !v.IsDevelopment() {
+			break
+		}
+		policy := f.historyPolicy(v, release, purgeTesting, purgeUnstable)
+		switch policy {
+		case KeepHistory:
+			kept = append(kept, f.el[i])
+		case SquashHistory:
+			squashed = append(squashed, f.el[i])
+		}
+		f.logger.Debug("history policy applied", logging.F("version", v.String()), logging.F("policy", policy))
+	}
+	f.el = append(kept, f.el[i:]...)
+
+	return
+}
+
+// squashChanges appends to the text of a new entry the changes of the
+// squashed entries which are not listed in it yet. The banners of the
+// snapshot and release entries are skipped.
+func squashChanges(clog string, entries Items) string {
+
+	listed := newListedChanges(clog)
+	out := clog
+	if out != "" && !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	for _, e := range entries {
+		for _, change := range Changes(e.Changelog) {
+			key := normalizeChange(change)
+			if strings.HasPrefix(change, "**") || key == "" || listed.subjects[key] {
+				continue
+			}
+			listed.subjects[key] = true
+			out += "  * " + strings.Replace(change, "\n", "\n    ", -1) + "\n"
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,166 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cinello/git-dch/pkg/dchversion"
+)
+
+func TestApplyHistoryPolicies(t *testing.T) {
+
+	snapshot := strings.Replace(mergeTestEntry("1.2.0~2.gbp1a2b3c", "Snapshot change\n  * Shared change"), "  * Snapshot", "  ** SNAPSHOT build @1a2b3c **\n\n  * Snapshot", 1)
+	staging := mergeTestEntry("1.2.0~stg-1", "Staging change\n    with details")
+	development := mergeTestEntry("1.2.0.20180214-1", "Shared change")
+	stable := mergeTestEntry("1.1.0-1", "Stable change")
+	old := mergeTestEntry("1.1.0~1.gbp4d5e6f", "Old snapshot")
+	text := strings.Join([]string{snapshot, staging, development, stable, old}, "\n")
+
+	tests := []struct {
+		name          string
+		policies      [3]HistoryPolicy
+		release       bool
+		purgeTesting  bool
+		purgeUnstable bool
+		want          []string
+		wantSquashed  string
+	}{
+		{name: `default`, release: true, want: []string{"1.2.0~stg-1", "1.2.0.20180214-1", "1.1.0-1", "1.1.0~1.gbp4d5e6f"}, wantSquashed: "  * New change\n"},
+		{name: `defaultAdd`, want: []string{"1.2.0~2.gbp1a2b3c", "1.2.0~stg-1", "1.2.0.20180214-1", "1.1.0-1", "1.1.0~1.gbp4d5e6f"}, wantSquashed: "  * New change\n"},
+		{
+			name:     `keep`,
+			policies: [3]HistoryPolicy{KeepHistory, KeepHistory, KeepHistory},
+			release:  true,
+			want:     []string{"1.2.0~2.gbp1a2b3c", "1.2.0~stg-1", "1.2.0.20180214-1", "1.1.0-1", "1.1.0~1.gbp4d5e6f"},
+		},
+		{
+			name:         `squash`,
+			policies:     [3]HistoryPolicy{SquashHistory, SquashHistory, SquashHistory},
+			release:      true,
+			want:         []string{"1.1.0-1", "1.1.0~1.gbp4d5e6f"},
+			wantSquashed: "  * New change\n  * Snapshot change\n  * Shared change\n  * Staging change\n    with details\n",
+		},
+		{
+			name:          `purgeFlags`,
+			policies:      [3]HistoryPolicy{KeepHistory, SquashHistory, SquashHistory},
+			release:       true,
+			purgeTesting:  true,
+			purgeUnstable: true,
+			want:          []string{"1.2.0~2.gbp1a2b3c", "1.1.0-1", "1.1.0~1.gbp4d5e6f"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(strings.NewReader(text))
+			if err != nil {
+				t.Fatalf("cannot read changelog: %s", err)
+			}
+			f.SetHistoryPolicies(tt.policies[0], tt.policies[1], tt.policies[2])
+
+			squashed := f.applyHistoryPolicies(tt.release, tt.purgeTesting, tt.purgeUnstable)
+			var got []string
+			for _, e := range f.el {
+				got = append(got, e.Version.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyHistoryPolicies() entries = %v, want %v", got, tt.want)
+			}
+
+			want := tt.wantSquashed
+			if want == "" {
+				want = "  * New change\n"
+			}
+			if got := squashChanges("  * New change\n", squashed); got != want {
+				t.Errorf("squashChanges() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestAddFailureKeepsHistory(t *testing.T) {
+
+	staging := mergeTestEntry("1.2.0~stg-1", "Staging change")
+	development := mergeTestEntry("1.2.0.20180214-1", "Development change")
+	stable := mergeTestEntry("1.1.0-1", "Stable change")
+	text := strings.Join([]string{staging, development, stable}, "\n")
+
+	tests := []struct {
+		name     string
+		policies [3]HistoryPolicy
+		release  bool
+		since    string
+		urgency  string
+	}{
+		{name: `releaseSquashBadSince`, policies: [3]HistoryPolicy{SquashHistory, SquashHistory, SquashHistory}, release: true, since: "0000000"},
+		{name: `releasePurgeBadUrgency`, policies: [3]HistoryPolicy{PurgeHistory, PurgeHistory, PurgeHistory}, release: true, since: "HEAD", urgency: "whenever"},
+		{name: `addSquashBadSince`, policies: [3]HistoryPolicy{SquashHistory, SquashHistory, SquashHistory}, since: "0000000"},
+		{name: `addPurgeBadUrgency`, policies: [3]HistoryPolicy{PurgeHistory, PurgeHistory, PurgeHistory}, since: "HEAD", urgency: "whenever"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(strings.NewReader(text))
+			if err != nil {
+				t.Fatalf("cannot read changelog: %s", err)
+			}
+			f.SetHistoryPolicies(tt.policies[0], tt.policies[1], tt.policies[2])
+			f.SetStrictSince(true)
+			before := append(Items(nil), f.el...)
+
+			ver := dchversion.NewVersionFromDebian(f.el[0].Version)
+			if tt.release {
+				_, _, err = f.AddRelease(tt.since, "", ver, tt.urgency, "testing", "", false, false, true, true)
+			} else {
+				_, _, err = f.Add(tt.since, "", ver, tt.urgency, "unstable", "", false, false, true, true)
+			}
+			if err == nil {
+				t.Fatalf("add succeeded, want an error")
+			}
+			if !reflect.DeepEqual(f.el, before) {
+				t.Errorf("entries after a failed add = %v, want %v", f.el, before)
+			}
+		})
+	}
+}
+
+func TestParseHistoryPolicy(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		value     string
+		want      HistoryPolicy
+		wantError bool
+	}{
+		{name: `default`, value: "", want: DefaultHistory},
+		{name: `keep`, value: "keep", want: KeepHistory},
+		{name: `squash`, value: "Squash", want: SquashHistory},
+		{name: `purge`, value: "purge", want: PurgeHistory},
+		{name: `invalid`, value: "drop", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHistoryPolicy(tt.value)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseHistoryPolicy() error = %v, wantError %v", err, tt.wantError)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHistoryPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cinello/git-dch/internal/errkind"
+	"github.com/cinello/git-dch/pkg/dchversion"
+	"github.com/cinello/git-dch/pkg/git"
+
+	"github.com/cinello/go-debian/version"
+)
+
+var (
+	regExMarkdownRelease  = regexp.MustCompile(`^##\s+\[?[vV]?(\d[^\]\s]*)\]?(?:\s*(?:-|–|—|\()\s*(\d{4}-\d{2}-\d{2})\)?)?`)
+	regExMarkdownSection  = regexp.MustCompile(`^###\s+(.+?)\s*$`)
+	regExMarkdownBullet   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	regExMarkdownLinkRef  = regexp.MustCompile(`^\s*\[[^\]]+\]:\s`)
+	regExMarkdownHeadline = regexp.MustCompile(`^#{1,2}\s`)
+)
+
+// initVersion returns the version of an entry created from a release:
+// native versions get the Debian revision 1
+func initVersion(v version.Version) dchversion.Version {
+
+	out := dchversion.NewVersionFromDebian(v)
+	if out.IsNative() {
+		out.SetRevision("1")
+	}
+
+	return out
+}
+
+// InitFromTags replaces the entries of the changelog with one entry for each
+// release tag of the repository (see git.Repository.ReleaseTags): the changes
+// are the commits between the previous tag and the tag, the maintainer is the
+// tagger and the date is the one of the tag.
+func (f *File) InitFromTags(source, target, urgency string, ignoreMerges bool) (err error) {
+
+	var gr git.Repository
+	if gr, err = f.repository(); err != nil {
+		return
+	}
+
+	var tags []git.ReleaseTag
+	if tags, err = gr.ReleaseTags(); err != nil {
+		return
+	}
+	if len(tags) == 0 {
+		return errkind.New(ErrNoReleases, "no release tags found in the repository")
+	}
+
+	f.el = Items{}
+	previous := ""
+	for i := len(tags) - 1; i >= 0; i-- {
+		tag := tags[i]
+		v := initVersion(tag.Version)
+
+		native := v.ExtractNative()
+		clog := fmt.Sprintf("  ** Release version %s\n\n", native.String())
+		var log string
+		if log, err = gr.LogBetween(previous, tag.Commit, false, false, true, false, ignoreMerges); err != nil {
+			return
+		}
+		clog += log
+
+		author := tag.Tagger.Name + " <" + tag.Tagger.Email + ">"
+		if err = f.initEntry(source, v, target, urgency, clog, author, tag.Tagger.When); err != nil {
+			return fmt.Errorf("cannot create the entry for tag %s: %w", tag.Name, err)
+		}
+		previous = tag.Commit
+	}
+
+	return
+}
+
+// initEntry adds an entry on top of the changelog, with the given date
+func (f *File) initEntry(source string, v dchversion.Version, target, urgency, clog, author string, when time.Time) (err error) {
+
+	if source, err = f.computeSourceName(source); err != nil {
+		return
+	}
+
+	var entry Item
+	if entry, err = NewItem(source, v, target, urgency, clog, author); err != nil {
+		return
+	}
+	entry.SetWhen(when)
+	f.el = append(Items{entry}, f.el...)
+
+	return
+}
+
+// markdownRelease is a release read from a Keep a Changelog file
+type markdownRelease struct {
+	version string
+	date    string
+	changes string
+}
+
+// parseMarkdownChangelog reads the releases of a changelog in the Keep a
+// Changelog format, from the newest to the oldest. The "### Added" like
+// sections become a bullet with the section changes as sub items; the
+// Unreleased section and the link references are ignored.
+func parseMarkdownChangelog(reader io.Reader) (releases []markdownRelease, err error) {
+
+	var (
+		current     *markdownRelease
+		section     string
+		sectionUsed bool
+		indent      string
+	)
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+
+		if values := regExMarkdownRelease.FindStringSubmatch(line); values != nil {
+			releases = append(releases, markdownRelease{version: values[1], date: values[2]})
+			current = &releases[len(releases)-1]
+			section, sectionUsed, indent = "", false, ""
+			continue
+		}
+		if regExMarkdownHeadline.MatchString(line) {
+			// title or unreleased changes
+			current = nil
+			continue
+		}
+		if current == nil || line == "" || regExMarkdownLinkRef.MatchString(line) {
+			continue
+		}
+		if values := regExMarkdownSection.FindStringSubmatch(line); values != nil {
+			section, sectionUsed, indent = values[1], false, ""
+			continue
+		}
+
+		text := strings.TrimSpace(line)
+		level := -1
+		if values := regExMarkdownBullet.FindStringSubmatch(line); values != nil {
+			text = values[2]
+			level = len(values[1]) / 2
+		}
+
+		switch {
+		case level == 0 || indent == "":
+			// a new change, a text outside a list is a change too
+			if section == "" {
+				current.changes += "  * " + text + "\n"
+				indent = "    "
+				break
+			}
+			if !sectionUsed {
+				current.changes += "  * " + section + ":\n"
+				sectionUsed = true
+			}
+			current.changes += "    - " + text + "\n"
+			indent = "      "
+		case level > 0:
+			current.changes += indent + strings.Repeat("  ", level-1) + "- " + text + "\n"
+		default:
+			current.changes += indent + text + "\n"
+		}
+	}
+
+	return releases, scanner.Err()
+}
+
+// InitFromMarkdown replaces the entries of the changelog with the releases
+// read from a changelog in the Keep a Changelog format, whose headings are
+// like "## [1.2.0] - 2018-02-14"
+func (f *File) InitFromMarkdown(reader io.Reader, source, target, urgency, author string) (err error) {
+
+	var releases []markdownRelease
+	if releases, err = parseMarkdownChangelog(reader); err != nil {
+		return
+	}
+	if len(releases) == 0 {
+		return errkind.New(ErrNoReleases, "no releases found in the markdown changelog")
+	}
+
+	f.el = Items{}
+	for i := len(releases) - 1; i >= 0; i-- {
+		r := releases[i]
+
+		var parsed version.Version
+		if parsed, err = version.Parse(r.version); err != nil {
+			return errkind.New(dchversion.ErrInvalidVersion, "the release %s has not a valid version: %s", r.version, err)
+		}
+		if r.date == "" {
+			return errkind.New(ErrInvalidEntry, "the release %s has no date", r.version)
+		}
+		var when time.Time
+		if when, err = time.Parse("2006-01-02", r.date); err != nil {
+			return errkind.New(ErrInvalidEntry, "the release %s has not a valid date: %s", r.version, err)
+		}
+
+		v := initVersion(parsed)
+		clog := r.changes
+		if clog == "" {
+			native := v.ExtractNative()
+			clog = fmt.Sprintf("  ** Release version %s\n", native.String())
+		}
+		if err = f.initEntry(source, v, target, urgency, clog, author, when); err != nil {
+			return fmt.Errorf("cannot create the entry for release %s: %w", r.version, err)
+		}
+	}
+
+	return
+}
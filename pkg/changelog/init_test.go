@@ -0,0 +1,97 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestInitFromMarkdown(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		markdown  string
+		want      string
+		wantError bool
+	}{
+		{
+			name: "keepAChangelog",
+			markdown: `# Changelog
+
+## [Unreleased]
+### Added
+- Not released yet
+
+## [1.1.0] - 2018-02-14
+### Added
+- New feature,
+  on two lines
+  - with a detail
+- Other feature
+### Fixed
+- A bug
+
+## 1.0.0 - 2018-01-02
+First release.
+
+[1.1.0]: https://example.com/compare/v1.0.0...v1.1.0
+`,
+			want: "pkg (1.1.0-1) unstable; urgency=medium\n\n" +
+				"  * Added:\n    - New feature,\n      on two lines\n      - with a detail\n    - Other feature\n" +
+				"  * Fixed:\n    - A bug\n\n" +
+				" -- A B <a@b.c>  Wed, 14 Feb 2018 00:00:00 +0000\n\n" +
+				"pkg (1.0.0-1) unstable; urgency=medium\n\n  * First release.\n\n" +
+				" -- A B <a@b.c>  Tue, 02 Jan 2018 00:00:00 +0000\n",
+		},
+		{
+			name:     "empty",
+			markdown: "## 1.0.0 (2018-01-02)\n",
+			want: "pkg (1.0.0-1) unstable; urgency=medium\n\n  ** Release version 1.0.0\n\n" +
+				" -- A B <a@b.c>  Tue, 02 Jan 2018 00:00:00 +0000\n",
+		},
+		{
+			name:      "noDate",
+			markdown:  "## [1.0.0]\n- Change\n",
+			wantError: true,
+		},
+		{
+			name:      "noReleases",
+			markdown:  "# Changelog\n\n## [Unreleased]\n- Change\n",
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &File{}
+			err := f.InitFromMarkdown(strings.NewReader(tt.markdown), "pkg", "unstable", "medium", "A B <a@b.c>")
+			if (err != nil) != tt.wantError {
+				t.Fatalf("InitFromMarkdown() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil {
+				return
+			}
+			var b bytes.Buffer
+			if _, err = f.Write(&b); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("InitFromMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
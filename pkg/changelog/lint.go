@@ -0,0 +1,237 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cinello/git-dch/pkg/dchversion"
+)
+
+var (
+	regExTrailerLine = regexp.MustCompile(`^ -- (\S(?:.*\S)?) <([^<>\s]+)>  (\S.*)$`)
+	regExEmptyChange = regexp.MustCompile(`^\s+\*\s*$`)
+)
+
+// Severity is the level of a Problem found by Lint
+type Severity string
+
+// The severity levels of the problems: errors make the changelog wrong or
+// unreadable by the Debian tools, warnings are style issues
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// The checks performed by Lint
+const (
+	CheckParse               = "parse"
+	CheckVersionOrder        = "version-order"
+	CheckDuplicateVersion    = "duplicate-version"
+	CheckDateOrder           = "date-order"
+	CheckInvalidDate         = "invalid-date"
+	CheckMalformedTrailer    = "malformed-trailer"
+	CheckUnknownDistribution = "unknown-distribution"
+	CheckTrailingWhitespace  = "trailing-whitespace"
+	CheckLineTooLong         = "line-too-long"
+	CheckEmptyEntry          = "empty-entry"
+)
+
+// Problem is an issue found by Lint at a line of a changelog.
+// Line is 0 for the problems not related to a single line.
+type Problem struct {
+	Line     int      `json:"line"`
+	Severity Severity `json:"severity"`
+	Check    string   `json:"check"`
+	Message  string   `json:"message"`
+}
+
+// String returns the problem in the "line: severity: message [check]" format
+func (p Problem) String() string {
+
+	return fmt.Sprintf("%d: %s: %s [%s]", p.Line, p.Severity, p.Message, p.Check)
+}
+
+// LintOptions configures the checks performed by Lint
+type LintOptions struct {
+	// MaxLineLength is the maximum length of the changes lines,
+	// zero disables the check
+	MaxLineLength int
+
+	// IsDistributionKnown reports if a target distribution is valid,
+	// nil disables the check. UNRELEASED is always accepted.
+	IsDistributionKnown func(distribution string) bool
+}
+
+// Lint checks the text of a changelog, returning the problems found sorted by line
+func Lint(data []byte, options LintOptions) (problems []Problem) {
+
+	headers := lintLines(string(data), options, &problems)
+
+	// the checks on the entries need a changelog readable by the parser
+	items, err := newItemListFromBytes(data)
+	if err != nil {
+		problems = append(problems, Problem{
+			Severity: SeverityError,
+			Check:    CheckParse,
+			Message:  fmt.Sprintf("cannot parse the changelog: %s", err),
+		})
+	} else {
+		lintItems(items, headers, options, &problems)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	return
+}
+
+// LintFile checks the changelog file at the given path, returning the problems
+// found sorted by line. An error is returned only if the file cannot be read.
+func LintFile(path string, options LintOptions) ([]Problem, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Lint(data, options), nil
+}
+
+// lintLines performs the checks on the single lines of a changelog,
+// returning the line numbers of the entry headers
+func lintLines(text string, options LintOptions, problems *[]Problem) (headers []int) {
+
+	add := func(line int, severity Severity, check, format string, a ...interface{}) {
+		*problems = append(*problems, Problem{Line: line, Severity: severity, Check: check, Message: fmt.Sprintf(format, a...)})
+	}
+
+	inEntry := false
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		n := i + 1
+
+		if strings.TrimRight(line, " \t\r") != line {
+			add(n, SeverityWarning, CheckTrailingWhitespace, "trailing whitespace")
+		}
+		line = strings.TrimRight(line, " \t\r")
+
+		switch {
+		case line == "":
+		case !strings.HasPrefix(line, " "):
+			// the parser reports the headers found inside an entry
+			if !inEntry {
+				headers = append(headers, n)
+				inEntry = true
+			}
+		case strings.HasPrefix(line, " --"):
+			lintTrailer(n, line, add)
+			inEntry = false
+		default:
+			if regExEmptyChange.MatchString(line) {
+				add(n, SeverityError, CheckEmptyEntry, "empty change entry")
+			}
+			if length := utf8.RuneCountInString(line); options.MaxLineLength > 0 && length > options.MaxLineLength {
+				add(n, SeverityWarning, CheckLineTooLong, "line is %d characters long, the maximum is %d", length, options.MaxLineLength)
+			}
+		}
+	}
+
+	return
+}
+
+// lintTrailer checks a " -- Name <email>  date" line
+func lintTrailer(n int, line string, add func(int, Severity, string, string, ...interface{})) {
+
+	values := regExTrailerLine.FindStringSubmatch(line)
+	if values == nil {
+		add(n, SeverityError, CheckMalformedTrailer, "the trailer line must be in the \" -- Name <email>  date\" format")
+		return
+	}
+
+	if _, err := parseTrailerDate(values[3]); err != nil {
+		add(n, SeverityError, CheckInvalidDate, "%s", err)
+	}
+}
+
+// parseTrailerDate parses the date of a trailer line, which must be in the
+// RFC 2822 format with the right day of the week
+func parseTrailerDate(value string) (t time.Time, err error) {
+
+	if t, err = time.Parse("Mon, _2 Jan 2006 15:04:05 -0700", value); err != nil {
+		return t, fmt.Errorf("the date %s is not in the RFC 2822 format", value)
+	}
+
+	// the parser checks only the syntax of the day of the week
+	if weekday := t.Weekday().String()[:3]; !strings.HasPrefix(value, weekday+",") {
+		return t, fmt.Errorf("the date %s is not valid, the day of the week should be %s", value, weekday)
+	}
+
+	return
+}
+
+// lintItems performs the checks on the parsed entries of a changelog;
+// headers are the line numbers of the entries
+func lintItems(items Items, headers []int, options LintOptions, problems *[]Problem) {
+
+	line := func(i int) int {
+		if i < len(headers) {
+			return headers[i]
+		}
+		return 0
+	}
+	add := func(i int, severity Severity, check, format string, a ...interface{}) {
+		*problems = append(*problems, Problem{Line: line(i), Severity: severity, Check: check, Message: fmt.Sprintf(format, a...)})
+	}
+
+	versions := make(map[string]int)
+	for i, item := range items {
+		v := item.Version.String()
+		if first, ok := versions[v]; ok {
+			add(i, SeverityError, CheckDuplicateVersion, "version %s is already used at line %d", v, line(first))
+		} else {
+			versions[v] = i
+		}
+
+		if options.IsDistributionKnown != nil {
+			for _, d := range item.Targets() {
+				if d != "UNRELEASED" && !options.IsDistributionKnown(d) {
+					add(i, SeverityWarning, CheckUnknownDistribution, "unknown distribution %s", d)
+				}
+			}
+		}
+
+		// entries are sorted from the newest to the oldest
+		if i+1 == len(items) {
+			continue
+		}
+		next := items[i+1]
+		current, previous := dchversion.NewVersionFromDebian(item.Version), dchversion.NewVersionFromDebian(next.Version)
+		if dchversion.Compare(current, previous) < 0 {
+			add(i, SeverityError, CheckVersionOrder, "version %s is lower than the previous version %s", v, next.Version.String())
+		}
+		if item.When.Before(next.When) {
+			add(i, SeverityWarning, CheckDateOrder, "the date is earlier than the one of the previous version %s", next.Version.String())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+
+	known := func(d string) bool {
+		return d == "unstable" || d == "bookworm"
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "ok",
+			text: "pkg (1.1-1) unstable; urgency=medium\n\n  * Change\n\n -- A B <a@b.c>  Tue, 13 Feb 2018 10:00:00 +0100\n\n" +
+				"pkg (1.0-1) UNRELEASED; urgency=medium\n\n  * Change\n\n -- A B <a@b.c>  Mon, 12 Feb 2018 10:00:00 +0100\n",
+			want: nil,
+		},
+		{
+			name: "order",
+			text: "pkg (1.0-1) unstable; urgency=medium\n\n  * Change\n\n -- A B <a@b.c>  Mon, 12 Feb 2018 10:00:00 +0100\n\n" +
+				"pkg (1.1-1) unstable; urgency=medium\n\n  * Change\n\n -- A B <a@b.c>  Tue, 13 Feb 2018 10:00:00 +0100\n\n" +
+				"pkg (1.0-1) unstable; urgency=medium\n\n  * Change\n\n -- A B <a@b.c>  Mon, 12 Feb 2018 10:00:00 +0100\n",
+			want: []string{
+				"1: error: version 1.0-1 is lower than the previous version 1.1-1 [version-order]",
+				"1: warning: the date is earlier than the one of the previous version 1.1-1 [date-order]",
+				"13: error: version 1.0-1 is already used at line 1 [duplicate-version]",
+			},
+		},
+		{
+			name: "lines",
+			text: "pkg (1.0-1) bookworm sid; urgency=medium\n\n  *\n  * A change longer than the limit \n\n -- A B <a@b.c>  Mon, 12 Feb 2018 10:00:00 +0100\n",
+			want: []string{
+				"1: warning: unknown distribution sid [unknown-distribution]",
+				"3: error: empty change entry [empty-entry]",
+				"4: warning: trailing whitespace [trailing-whitespace]",
+				"4: warning: line is 34 characters long, the maximum is 30 [line-too-long]",
+			},
+		},
+		{
+			name: "trailer",
+			text: "pkg (1.0-1) unstable; urgency=medium\n\n  * Change\n\n --A B <a@b.c> Mon, 12 Feb 2018 10:00:00 +0100\n",
+			want: []string{
+				"5: error: the trailer line must be in the \" -- Name <email>  date\" format [malformed-trailer]",
+			},
+		},
+		{
+			name: "date",
+			text: "pkg (1.0-1) unstable; urgency=medium\n\n  * Change\n\n -- A B <a@b.c>  Tue, 12 Feb 2018 10:00:00 +0100\n",
+			want: []string{
+				"5: error: the date Tue, 12 Feb 2018 10:00:00 +0100 is not valid, the day of the week should be Mon [invalid-date]",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, p := range Lint([]byte(tt.text), LintOptions{MaxLineLength: 30, IsDistributionKnown: known}) {
+				got = append(got, p.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cinello/git-dch/pkg/dchversion"
+)
+
+// entriesByVersion returns the text of the entries, indexed by version
+func entriesByVersion(items Items, versions map[string]dchversion.Version) map[string]string {
+
+	out := make(map[string]string)
+	for i := range items {
+		v := dchversion.NewVersionFromDebian(items[i].Version)
+		out[v.String()] = strings.Trim(items[i].String(), "\n") + "\n"
+		versions[v.String()] = v
+	}
+
+	return out
+}
+
+// Merge performs a three way merge of a changelog, as dpkg-mergechangelogs:
+// the entries of the three versions are merged by version and sorted with
+// dchversion.Compare. An entry changed, added or removed on one side only
+// gets the change, an entry changed differently on both sides is a conflict,
+// written between conflict markers. The versions in conflict are returned.
+func Merge(base, ours, theirs Items) (text string, conflicts []string) {
+
+	versions := make(map[string]dchversion.Version)
+	o := entriesByVersion(base, versions)
+	a := entriesByVersion(ours, versions)
+	b := entriesByVersion(theirs, versions)
+
+	var sorted []dchversion.Version
+	for _, v := range versions {
+		sorted = append(sorted, v)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return dchversion.Compare(sorted[i], sorted[j]) > 0
+	})
+
+	var entries []string
+	for _, v := range sorted {
+		key := v.String()
+		switch {
+		case a[key] == b[key]:
+			entries = append(entries, a[key])
+		case a[key] == o[key]:
+			entries = append(entries, b[key])
+		case b[key] == o[key]:
+			entries = append(entries, a[key])
+		default:
+			conflicts = append(conflicts, key)
+			entries = append(entries, "<<<<<<< ours\n"+a[key]+"=======\n"+b[key]+">>>>>>> theirs\n")
+		}
+	}
+
+	// the entries removed on a side are empty strings
+	var out []string
+	for _, e := range entries {
+		if e != "" {
+			out = append(out, e)
+		}
+	}
+
+	return strings.Join(out, "\n"), conflicts
+}
@@ -0,0 +1,77 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mergeTestEntry(version, change string) string {
+
+	return "pkg (" + version + ") unstable; urgency=medium\n\n  * " + change + "\n\n -- A B <a@b.c>  Mon, 12 Feb 2018 10:00:00 +0100\n"
+}
+
+func TestMerge(t *testing.T) {
+
+	v10 := mergeTestEntry("1.0-1", "First")
+	v11 := mergeTestEntry("1.1-1", "Second")
+	v11b := mergeTestEntry("1.1-1", "Second, fixed")
+	v11c := mergeTestEntry("1.1-1", "Second, changed")
+	v12 := mergeTestEntry("1.2-1", "Third")
+	v20 := mergeTestEntry("2.0~stg-1", "Staging")
+
+	tests := []struct {
+		name          string
+		base          []string
+		ours          []string
+		theirs        []string
+		want          []string
+		wantConflicts []string
+	}{
+		{name: `union`, base: []string{v10}, ours: []string{v11, v10}, theirs: []string{v20, v10}, want: []string{v20, v11, v10}},
+		{name: `sameAdded`, base: []string{v10}, ours: []string{v12, v10}, theirs: []string{v12, v10}, want: []string{v12, v10}},
+		{name: `changedOurs`, base: []string{v11, v10}, ours: []string{v11b, v10}, theirs: []string{v12, v11, v10}, want: []string{v12, v11b, v10}},
+		{name: `removedTheirs`, base: []string{v11, v10}, ours: []string{v11, v10}, theirs: []string{v10}, want: []string{v10}},
+		{
+			name:          `conflict`,
+			base:          []string{v11, v10},
+			ours:          []string{v11b, v10},
+			theirs:        []string{v11c, v10},
+			want:          []string{"<<<<<<< ours\n" + v11b + "=======\n" + v11c + ">>>>>>> theirs\n", v10},
+			wantConflicts: []string{"1.1-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var items [3]Items
+			for i, entries := range [][]string{tt.base, tt.ours, tt.theirs} {
+				var err error
+				if items[i], err = NewItemList(strings.NewReader(strings.Join(entries, "\n"))); err != nil {
+					t.Fatalf("NewItemList() error = %v", err)
+				}
+			}
+			got, conflicts := Merge(items[0], items[1], items[2])
+			if want := strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("Merge() = %q, want %q", got, want)
+			}
+			if !reflect.DeepEqual(conflicts, tt.wantConflicts) {
+				t.Errorf("Merge() conflicts = %v, want %v", conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cinello/git-dch/internal/errkind"
+	"github.com/cinello/git-dch/pkg/dchversion"
+)
+
+var (
+	regExCloses    = regexp.MustCompile(`(?i)closes:\s*(?:bug)?#?\s?\d+(?:,\s*(?:bug)?#?\s?\d+)*`)
+	regExBugNumber = regexp.MustCompile(`\d+`)
+
+	urgencyLevels = map[string]int{
+		"low":       1,
+		"medium":    2,
+		"high":      3,
+		"emergency": 4,
+		"critical":  4,
+	}
+)
+
+// SummaryFields are the names of the Summary fields, in the order they are written
+var SummaryFields = []string{
+	"Source",
+	"Version",
+	"Distribution",
+	"Urgency",
+	"Maintainer",
+	"Timestamp",
+	"Date",
+	"Closes",
+	"Changes",
+}
+
+// Summary describes a range of changelog entries, with the same
+// fields printed by dpkg-parsechangelog
+type Summary struct {
+	Source       string   `json:"source"`
+	Version      string   `json:"version"`
+	Distribution string   `json:"distribution"`
+	Urgency      string   `json:"urgency"`
+	Maintainer   string   `json:"maintainer"`
+	Timestamp    int64    `json:"timestamp"`
+	Date         string   `json:"date"`
+	Closes       []string `json:"closes"`
+	Changes      string   `json:"changes"`
+}
+
+// Range returns the entries with a version greater than since and lower than
+// until, limited to the newest count ones. Empty since and until values
+// don't limit the range; if no limit is given only the last entry is returned.
+func (e Items) Range(count int, since, until string) (out Items, err error) {
+
+	var sinceVersion, untilVersion dchversion.Version
+	if since != "" {
+		if sinceVersion, err = dchversion.Parse(since); err != nil {
+			return nil, fmt.Errorf("the version %s is not valid: %w", since, err)
+		}
+	}
+	if until != "" {
+		if untilVersion, err = dchversion.Parse(until); err != nil {
+			return nil, fmt.Errorf("the version %s is not valid: %w", until, err)
+		}
+	}
+	if count <= 0 && since == "" && until == "" {
+		count = 1
+	}
+
+	out = Items{}
+	for _, item := range e {
+		v := dchversion.NewVersionFromDebian(item.Version)
+		if since != "" && dchversion.Compare(v, sinceVersion) <= 0 {
+			continue
+		}
+		if until != "" && dchversion.Compare(v, untilVersion) >= 0 {
+			continue
+		}
+		if count > 0 && len(out) == count {
+			break
+		}
+		out = append(out, item)
+	}
+
+	return
+}
+
+// Summary returns the description of the entries: the fields of the
+// (chronologically) last entry, the highest urgency, the bugs closed
+// and the changes of all the entries
+func (e Items) Summary() (s Summary) {
+
+	s.Source = e.Source()
+	s.Version = e.Version().String()
+	s.Distribution = e.Target()
+	s.Maintainer = e.Author()
+	s.Timestamp = e.When().Unix()
+	s.Date = WhenToString(e.When())
+	s.Closes = []string{}
+
+	closes := make(map[string]bool)
+	var changes []string
+	for _, item := range e {
+		if u := item.Arguments["urgency"]; urgencyLevel(u) > urgencyLevel(s.Urgency) {
+			s.Urgency = u
+		}
+		for _, bug := range Closes(item.Changelog) {
+			if !closes[bug] {
+				closes[bug] = true
+				s.Closes = append(s.Closes, bug)
+			}
+		}
+		changes = append(changes, item.Header()+"\n\n"+strings.Trim(item.Changelog, "\n"))
+	}
+	sort.Slice(s.Closes, func(i, j int) bool {
+		a, _ := strconv.Atoi(s.Closes[i])
+		b, _ := strconv.Atoi(s.Closes[j])
+		return a < b
+	})
+	s.Changes = strings.Join(changes, "\n\n")
+
+	return
+}
+
+// urgencyLevel returns the priority of an urgency value, ignoring its comment
+func urgencyLevel(value string) int {
+
+	return urgencyLevels[strings.ToLower(strings.TrimSpace(strings.SplitN(value, "(", 2)[0]))]
+}
+
+// Closes returns the numbers of the bugs closed in a changes text,
+// written as "Closes: #123, #456"
+func Closes(text string) (bugs []string) {
+
+	for _, closes := range regExCloses.FindAllString(text, -1) {
+		bugs = append(bugs, regExBugNumber.FindAllString(closes, -1)...)
+	}
+
+	return
+}
+
+// Field returns the value of a field of the summary, the name is case insensitive
+func (s Summary) Field(name string) (string, error) {
+
+	switch strings.ToLower(name) {
+	case "source":
+		return s.Source, nil
+	case "version":
+		return s.Version, nil
+	case "distribution":
+		return s.Distribution, nil
+	case "urgency":
+		return s.Urgency, nil
+	case "maintainer":
+		return s.Maintainer, nil
+	case "timestamp":
+		return strconv.FormatInt(s.Timestamp, 10), nil
+	case "date":
+		return s.Date, nil
+	case "closes":
+		return strings.Join(s.Closes, " "), nil
+	case "changes":
+		return s.Changes, nil
+	}
+
+	return "", errkind.New(ErrInvalidEntry, "unknown field %s", name)
+}
+
+// Deb822 returns the summary in the deb822 format, as printed by dpkg-parsechangelog.
+// The multiline Changes field starts on the line after its name.
+func (s Summary) Deb822() (out string) {
+
+	for _, name := range SummaryFields {
+		value, _ := s.Field(name)
+		switch {
+		case name == "Closes" && value == "":
+		case name == "Changes":
+			out += name + ":\n" + FoldFieldValue(value) + "\n"
+		default:
+			out += name + ": " + value + "\n"
+		}
+	}
+
+	return
+}
+
+// FoldFieldValue formats a multiline text as the continuation lines of a
+// deb822 field: each line starts with a space, empty lines are written as " ."
+func FoldFieldValue(text string) string {
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			line = "."
+		}
+		lines[i] = " " + line
+	}
+
+	return strings.Join(lines, "\n")
+}
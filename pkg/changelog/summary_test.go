@@ -0,0 +1,108 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+const summaryChangelog = `pkg (1.2-1) bookworm; urgency=low
+
+  * Third change. Closes: #300
+
+ -- A B <a@b.c>  Wed, 14 Feb 2018 10:00:00 +0100
+
+pkg (1.1-1) unstable; urgency=high (security)
+
+  * Second change. Closes: #200, #1000
+
+ -- C D <c@d.e>  Tue, 13 Feb 2018 10:00:00 +0100
+
+pkg (1.0-1) unstable; urgency=medium
+
+  * First change
+
+ -- C D <c@d.e>  Mon, 12 Feb 2018 10:00:00 +0100
+`
+
+func TestItemsSummary(t *testing.T) {
+
+	items, err := NewItemList(strings.NewReader(summaryChangelog))
+	if err != nil {
+		t.Fatalf("NewItemList() error = %v", err)
+	}
+
+	type args struct {
+		count int
+		since string
+		until string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      string
+		wantError bool
+	}{
+		{
+			name: "last",
+			args: args{},
+			want: "Source: pkg\nVersion: 1.2-1\nDistribution: bookworm\nUrgency: low\n" +
+				"Maintainer: A B <a@b.c>\nTimestamp: 1518598800\nDate: Wed, 14 Feb 2018 10:00:00 +0100\nCloses: 300\n" +
+				"Changes:\n pkg (1.2-1) bookworm; urgency=low\n .\n   * Third change. Closes: #300\n",
+		},
+		{
+			name: "since",
+			args: args{since: "1.0-1"},
+			want: "Source: pkg\nVersion: 1.2-1\nDistribution: bookworm\nUrgency: high (security)\n" +
+				"Maintainer: A B <a@b.c>\nTimestamp: 1518598800\nDate: Wed, 14 Feb 2018 10:00:00 +0100\nCloses: 200 300 1000\n" +
+				"Changes:\n pkg (1.2-1) bookworm; urgency=low\n .\n   * Third change. Closes: #300\n .\n" +
+				" pkg (1.1-1) unstable; urgency=high (security)\n .\n   * Second change. Closes: #200, #1000\n",
+		},
+		{
+			name: "until",
+			args: args{count: 1, until: "1.2-1"},
+			want: "Source: pkg\nVersion: 1.1-1\nDistribution: unstable\nUrgency: high (security)\n" +
+				"Maintainer: C D <c@d.e>\nTimestamp: 1518512400\nDate: Tue, 13 Feb 2018 10:00:00 +0100\nCloses: 200 1000\n" +
+				"Changes:\n pkg (1.1-1) unstable; urgency=high (security)\n .\n   * Second change. Closes: #200, #1000\n",
+		},
+		{
+			name:      "versionError",
+			args:      args{since: "a b"},
+			wantError: true,
+		},
+		{
+			name:      "empty",
+			args:      args{since: "1.2-1"},
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &File{el: items}
+			s, err := f.Summary(tt.args.count, tt.args.since, tt.args.until)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Summary() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil {
+				return
+			}
+			if got := s.Deb822(); got != tt.want {
+				t.Errorf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
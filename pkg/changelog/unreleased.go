@@ -0,0 +1,221 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cinello/git-dch/internal/errkind"
+	"github.com/cinello/git-dch/pkg/dchversion"
+	"github.com/cinello/git-dch/pkg/git"
+	"github.com/cinello/git-dch/pkg/logging"
+
+	"github.com/cinello/go-debian/version"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+const (
+	// unreleasedTarget is the distribution of the entries not released yet
+	unreleasedTarget = "UNRELEASED"
+	// defaultTarget is the distribution of a finalized entry, when neither
+	// given nor found in the previous entry
+	defaultTarget = "unstable"
+)
+
+var (
+	// regExChangePrefix matches the commit hash written at the start of a
+	// change, as "[0123456] subject"
+	regExChangePrefix = regexp.MustCompile(`^\[([0-9a-f]{7,40})\]\s*`)
+)
+
+// SetMergeUnreleased configures Add to append the new changes to the
+// UNRELEASED entry on top of the changelog, as dch does, instead of
+// adding a new entry
+func (f *File) SetMergeUnreleased(merge bool) {
+
+	f.mergeUnreleased = merge
+}
+
+// HasOpenEntry returns true if the entry on top of the changelog is not
+// released yet: its distribution is UNRELEASED and it is not a snapshot
+func (f *File) HasOpenEntry() bool {
+
+	if f.IsEmpty() || f.el.Target() != unreleasedTarget {
+		return false
+	}
+	v := dchversion.NewVersionFromDebian(f.el.Version())
+
+	return !v.IsSnapshot()
+}
+
+// normalizeChange returns a change text without the commit hash prefix and
+// with the spaces collapsed, to compare it with a commit subject
+func normalizeChange(text string) string {
+
+	text = regExChangePrefix.ReplaceAllString(strings.TrimSpace(text), "")
+
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// listedChanges tracks the changes already listed in an entry
+type listedChanges struct {
+	hashes   []string
+	subjects map[string]bool
+}
+
+// newListedChanges reads the commit hashes and the subjects of the changes
+// of an entry text; only a hash at the start of a change is read, so that the
+// words and the hashes quoted in the text are not taken as listed commits
+func newListedChanges(text string) (l listedChanges) {
+
+	l.subjects = make(map[string]bool)
+	for _, change := range Changes(text) {
+		if values := regExChangePrefix.FindStringSubmatch(change); values != nil {
+			l.hashes = append(l.hashes, values[1])
+		}
+		l.subjects[normalizeChange(change)] = true
+		// a wrapped subject, or a subject followed by a description
+		l.subjects[normalizeChange(strings.SplitN(change, "\n", 2)[0])] = true
+	}
+
+	return
+}
+
+// contains returns true if the commit is already listed, by its hash or by
+// its subject
+func (l listedChanges) contains(c *object.Commit) bool {
+
+	for _, hash := range l.hashes {
+		if strings.HasPrefix(c.Hash.String(), hash) {
+			return true
+		}
+	}
+	subject := strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0]
+
+	return l.subjects[normalizeChange(subject)]
+}
+
+// addToOpenEntry appends to the UNRELEASED entry on top of the changelog the
+// commits not listed in it yet, updating its version and its date. The version
+// is computed as for a new entry following the released ones, but it never
+// goes back from the version of the open entry.
+func (f *File) addToOpenEntry(
+	since string,
+	ver dchversion.Version,
+	auto, ignoreMerges bool,
+) (v dchversion.Version, entry Item, err error) {
+
+	var gr git.Repository
+	if gr, err = f.repository(); err != nil {
+		return
+	}
+
+	var list []*object.Commit
+	if list, err = f.getCommits(gr, since, auto, ignoreMerges); err != nil {
+		return
+	}
+
+	entry = f.el[0]
+	listed := newListedChanges(entry.Changelog)
+	var added []*object.Commit
+	for _, c := range list {
+		if !listed.contains(c) {
+			added = append(added, c)
+		}
+	}
+
+	released := File{el: f.el[1:]}
+	if v, err = released.computeNewVersion(ver); err != nil {
+		return
+	}
+	if old := dchversion.NewVersionFromDebian(entry.Version); dchversion.Compare(v, old) < 0 {
+		f.logger.Info("version of the UNRELEASED entry kept, it is greater than the computed one",
+			logging.F("version", old.String()), logging.F("computed", v.String()))
+		v = old
+	}
+	f.logger.Info("changes added to the UNRELEASED entry",
+		logging.F("version", v.String()), logging.F("added", len(added)), logging.F("skipped", len(list)-len(added)))
+	if err = entry.SetVersion(version.Version{Epoch: v.Epoch(), Version: v.Version(), Revision: v.Revision()}); err != nil {
+		return
+	}
+
+	if log := gr.LogCommits(added, false, false, true, false); log != "" {
+		clog := strings.Trim(entry.Changelog, "\n")
+		if strings.TrimSpace(clog) == "*" {
+			// the placeholder of an empty entry
+			clog = ""
+		}
+		if clog != "" {
+			clog += "\n"
+		}
+		entry.SetChangelog(clog + log)
+	}
+	entry.SetWhen(time.Now())
+
+	f.el[0] = entry
+
+	return v, entry, nil
+}
+
+// Finalize turns the UNRELEASED entry on top of the changelog into a release,
+// as dch -r: the text of the entry is kept, while its version is built from ver
+// as a release version and its distribution, urgency and date are replaced. An
+// empty urgency keeps the one of the entry, an empty target takes the
+// distribution of the previous entry. A snapshot entry cannot be finalized.
+func (f *File) Finalize(ver dchversion.Version, urgency, target string) (v dchversion.Version, err error) {
+
+	if !f.HasOpenEntry() {
+		return v, errkind.New(ErrNoOpenEntry, "the changelog has no UNRELEASED entry to finalize")
+	}
+
+	if v, err = ver.Build(dchversion.Release); err != nil {
+		return v, fmt.Errorf("cannot create a release version from value %s: %w", ver.String(), err)
+	}
+	if len(f.el) > 1 {
+		previous := dchversion.NewVersionFromDebian(f.el[1].Version)
+		if dchversion.Compare(v, previous) <= 0 {
+			return v, errkind.New(ErrVersionRegression, "the release version %s is not greater than the previous version %s", v.String(), previous.String())
+		}
+	}
+
+	entry := f.el[0]
+	if err = entry.SetVersion(version.Version{Epoch: v.Epoch(), Version: v.Version(), Revision: v.Revision()}); err != nil {
+		return
+	}
+	targets := strings.Fields(target)
+	if len(targets) == 0 && len(f.el) > 1 {
+		targets = f.el[1].Targets()
+	}
+	if len(targets) == 0 {
+		targets = []string{defaultTarget}
+	}
+	if err = entry.SetTargets(targets...); err != nil {
+		return
+	}
+	if urgency != "" {
+		if err = entry.SetUrgency(urgency); err != nil {
+			return
+		}
+	}
+	entry.SetWhen(time.Now())
+
+	f.el[0] = entry
+
+	return v, nil
+}
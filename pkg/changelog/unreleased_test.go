@@ -0,0 +1,151 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package changelog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cinello/git-dch/pkg/dchversion"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestHasOpenEntry(t *testing.T) {
+
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{name: `empty`, text: "", want: false},
+		{name: `released`, text: mergeTestEntry("1.0-1", "First"), want: false},
+		{name: `unreleased`, text: strings.Replace(mergeTestEntry("1.1-1", "Second"), "unstable", "UNRELEASED", 1), want: true},
+		{
+			name: `snapshot`,
+			text: strings.Replace(mergeTestEntry("1.1.0~3.gbp1a2b3c", "Second"), "unstable", "UNRELEASED", 1),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(strings.NewReader(tt.text))
+			if err != nil {
+				t.Fatalf("cannot read changelog: %s", err)
+			}
+			if got := f.HasOpenEntry(); got != tt.want {
+				t.Errorf("HasOpenEntry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListedChanges(t *testing.T) {
+
+	const text = `
+  * [0123456] Add the feature
+  * Fix a very long subject, wrapped
+    on two lines
+  * Update the documentation
+    with a description
+  * Cherry-picked 89abcdef01 from upstream
+  * Remove the defaced logo
+  * [fedcba9] Fix the build
+
+`
+	hash := func(prefix string) plumbing.Hash {
+		return plumbing.NewHash(prefix + strings.Repeat("f", 40-len(prefix)))
+	}
+
+	tests := []struct {
+		name    string
+		hash    plumbing.Hash
+		message string
+		want    bool
+	}{
+		{name: `hashPrefix`, hash: hash("0123456"), message: "Something else", want: true},
+		{name: `hashInText`, hash: hash("89abcdef01"), message: "Other change", want: false},
+		{name: `hexWord`, hash: hash("defaced"), message: "Other change", want: false},
+		{name: `secondHashPrefix`, hash: hash("fedcba9"), message: "Something else", want: true},
+		{name: `subject`, hash: hash("1111111"), message: "Add   the feature\n\nWith a body", want: true},
+		{name: `wrapped`, hash: hash("1111111"), message: "Fix a very long subject, wrapped on two lines", want: true},
+		{name: `firstLine`, hash: hash("1111111"), message: "Update the documentation", want: true},
+		{name: `new`, hash: hash("1111111"), message: "Add another feature", want: false},
+	}
+	listed := newListedChanges(text)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &object.Commit{Hash: tt.hash, Message: tt.message}
+			if got := listed.contains(c); got != tt.want {
+				t.Errorf("contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFinalize(t *testing.T) {
+
+	unreleased := strings.Replace(mergeTestEntry("1.1.0~stg-1", "Hand written\n    notes"), "unstable; urgency=medium", "UNRELEASED; urgency=low", 1)
+	released := mergeTestEntry("1.0-1", "First")
+	security := strings.Replace(released, "unstable;", "stable-security;", 1)
+	snapshot := strings.Replace(mergeTestEntry("1.1.0~3.gbp1a2b3c", "Hand written\n    notes"), "unstable", "UNRELEASED", 1)
+
+	tests := []struct {
+		name       string
+		text       string
+		version    string
+		urgency    string
+		target     string
+		wantHeader string
+		wantError  error
+	}{
+		{name: `staging`, text: unreleased + "\n" + released, version: "1.1.0~stg-1", target: "stable", wantHeader: "pkg (1.1.0-1) stable; urgency=low"},
+		{name: `native`, text: unreleased, version: "1.2", urgency: "high", target: "stable stable-security", wantHeader: "pkg (1.2-1) stable stable-security; urgency=high"},
+		{name: `released`, text: released, version: "1.0-1", target: "stable", wantError: ErrNoOpenEntry},
+		{name: `notGreater`, text: unreleased + "\n" + released, version: "1.0", target: "stable", wantError: ErrVersionRegression},
+		{name: `keepUrgency`, text: unreleased, version: "1.2", target: "stable", wantHeader: "pkg (1.2-1) stable; urgency=low"},
+		{name: `previousTarget`, text: unreleased + "\n" + security, version: "1.1.0~stg-1", wantHeader: "pkg (1.1.0-1) stable-security; urgency=low"},
+		{name: `defaultTarget`, text: unreleased, version: "1.1.0~stg-1", wantHeader: "pkg (1.1.0-1) unstable; urgency=low"},
+		{name: `snapshot`, text: snapshot + "\n" + released, version: "1.1.0", target: "stable", wantError: ErrNoOpenEntry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(strings.NewReader(tt.text))
+			if err != nil {
+				t.Fatalf("cannot read changelog: %s", err)
+			}
+			ver, err := dchversion.Parse(tt.version)
+			if err != nil {
+				t.Fatalf("cannot parse version: %s", err)
+			}
+			_, err = f.Finalize(ver, tt.urgency, tt.target)
+			if !errors.Is(err, tt.wantError) || (err != nil) != (tt.wantError != nil) {
+				t.Fatalf("Finalize() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil {
+				return
+			}
+			if got := f.el[0].Header(); got != tt.wantHeader {
+				t.Errorf("Finalize() header = %q, want %q", got, tt.wantHeader)
+			}
+			if want := "\n  * Hand written\n    notes\n\n"; f.el[0].Changelog != want {
+				t.Errorf("Finalize() changes = %q, want %q", f.el[0].Changelog, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package dchversion
+
+import (
+	"errors"
+)
+
+var (
+	// ErrInvalidVersion is returned when a value is not a valid Debian version
+	ErrInvalidVersion = errors.New("invalid version")
+	// ErrIncompatibleVersion is returned when a version cannot be built as
+	// the requested release type
+	ErrIncompatibleVersion = errors.New("incompatible version")
+	// ErrNotSnapshot is returned when a snapshot version is expected
+	ErrNotSnapshot = errors.New("not a snapshot version")
+)
@@ -24,6 +24,20 @@ const (
 	Snapshot
 )
 
+func (t ReleaseType) String() string {
+	switch t {
+	case Release:
+		return "release"
+	case Staging:
+		return "staging"
+	case Development:
+		return "development"
+	case Snapshot:
+		return "snapshot"
+	}
+	return "unknown"
+}
+
 func (t ReleaseType) SourceBranch() string {
 	switch t {
 	case Release:
@@ -69,6 +69,27 @@ func TestReleaseTypeSourceBranch(t *testing.T) {
 	}
 }
 
+func TestReleaseTypeString(t *testing.T) {
+	tests := []struct {
+		name string
+		t    ReleaseType
+		want string
+	}{
+		{name: `release`, t: Release, want: "release"},
+		{name: `staging`, t: Staging, want: "staging"},
+		{name: `development`, t: Development, want: "development"},
+		{name: `snapshot`, t: Snapshot, want: "snapshot"},
+		{name: `unknown`, t: ReleaseType(10), want: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.t.String(); got != tt.want {
+				t.Errorf("ReleaseType.String() = '%v', want '%v'", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestReleaseTypeFromBranch(t *testing.T) {
 	type args struct {
 		b string
@@ -19,8 +19,10 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/cinello/git-dch/internal/errkind"
 	"github.com/cinello/git-dch/pkg/git"
 
 	"github.com/cinello/go-debian/version"
@@ -56,6 +58,9 @@ func NewVersionFromDebian(v version.Version) Version {
 func Parse(input string) (Version, error) {
 
 	value, err := version.Parse(input)
+	if err != nil {
+		err = errkind.New(ErrInvalidVersion, "%w", err)
+	}
 	return Version{v: value}, err
 }
 
@@ -179,7 +184,7 @@ func (v Version) Build(t ReleaseType) (out Version, err error) {
 			out.v.Revision = "1"
 		case out.IsStable():
 		default:
-			err = fmt.Errorf("cannot build stable dchversion from %s", out.String())
+			err = errkind.New(ErrIncompatibleVersion, "cannot build stable dchversion from %s", out.String())
 			return
 		}
 	case Staging:
@@ -195,7 +200,7 @@ func (v Version) Build(t ReleaseType) (out Version, err error) {
 			out.v.Version += "~stg"
 			out.v.Revision = "1"
 		default:
-			err = fmt.Errorf("cannot build staging dchversion from %s", out.String())
+			err = errkind.New(ErrIncompatibleVersion, "cannot build staging dchversion from %s", out.String())
 			return
 		}
 	case Development:
@@ -205,7 +210,7 @@ func (v Version) Build(t ReleaseType) (out Version, err error) {
 			out.v.Version += "." + developmentDate()
 			out.v.Revision = "1"
 		default:
-			err = fmt.Errorf("cannot build development dchversion from %s", out.String())
+			err = errkind.New(ErrIncompatibleVersion, "cannot build development dchversion from %s", out.String())
 			return
 		}
 	case Snapshot:
@@ -226,7 +231,7 @@ func (v Version) Build(t ReleaseType) (out Version, err error) {
 			out.v.Version += "~1.gbp" + hash
 			out.v.Revision = ""
 		default:
-			err = fmt.Errorf("cannot build snapshot dchversion from %s", out.String())
+			err = errkind.New(ErrIncompatibleVersion, "cannot build snapshot dchversion from %s", out.String())
 			return
 		}
 	}
@@ -245,7 +250,7 @@ func (v Version) IncrementRevision() (newVersion Version, err error) {
 		values := regExSplitSnapshotVersion.FindAllStringSubmatch(v.v.Version, -1)
 		revision, err = strconv.ParseInt(values[0][2], 10, 64)
 		if err != nil {
-			err = fmt.Errorf("cannot get the revision from the value %s: %s", v.v.String(), err)
+			err = errkind.New(ErrInvalidVersion, "cannot get the revision from the value %s: %w", v.v.String(), err)
 			return
 		}
 
@@ -256,10 +261,7 @@ func (v Version) IncrementRevision() (newVersion Version, err error) {
 		}
 		var hash string
 		if hash, err = gr.LastCommitHash(6); err != nil {
-			return
-		}
-		if err != nil {
-			err = fmt.Errorf("cannot get the hash from last git commit %s", err)
+			err = fmt.Errorf("cannot get the hash from last git commit: %w", err)
 			return
 		}
 
@@ -272,12 +274,12 @@ func (v Version) IncrementRevision() (newVersion Version, err error) {
 		// Split revision number from any text before
 		values := regExSplitRevision.FindAllStringSubmatch(v.v.Revision, -1)
 		if len(values) != 1 || len(values[0]) != 3 {
-			err = fmt.Errorf("cannot find valid revision number in %s", v.v.String())
+			err = errkind.New(ErrInvalidVersion, "cannot find valid revision number in %s", v.v.String())
 			return
 		}
 		revision, err = strconv.ParseInt(values[0][2], 10, 64)
 		if err != nil {
-			err = fmt.Errorf("cannot get the revision from the value %s: %s", v.v.String(), err)
+			err = errkind.New(ErrInvalidVersion, "cannot get the revision from the value %s: %w", v.v.String(), err)
 			return
 		}
 
@@ -313,11 +315,11 @@ func CompareStrings(a string, b string) (int, error) {
 	var err error
 	va, err := version.Parse(a)
 	if err != nil {
-		return 0, fmt.Errorf("value %s is not a valid dchversion", a)
+		return 0, errkind.New(ErrInvalidVersion, "value %s is not a valid dchversion", a)
 	}
 	vb, err := version.Parse(b)
 	if err != nil {
-		return 0, fmt.Errorf("value %s is not a valid dchversion", b)
+		return 0, errkind.New(ErrInvalidVersion, "value %s is not a valid dchversion", b)
 	}
 
 	return version.Compare(va, vb), nil
@@ -325,16 +327,26 @@ func CompareStrings(a string, b string) (int, error) {
 
 func GetSnapshotRelease(v Version) (r int, err error) {
 	s := regExSplitSnapshotVersion.FindAllStringSubmatch(v.v.Version, -1)
-	if len(s) != 1 && len(s[0]) < 4 {
-		return r, fmt.Errorf("the dchversion %s is not a valid snapshot", v.v.String())
+	if len(s) != 1 || len(s[0]) < 4 {
+		return r, errkind.New(ErrNotSnapshot, "the dchversion %s is not a valid snapshot", v.v.String())
 	}
 	return strconv.Atoi(s[0][2])
 }
 
+// GetSnapshotHash returns the abbreviated hash of the commit encoded in a
+// snapshot version
+func GetSnapshotHash(v Version) (hash string, err error) {
+	s := regExSplitSnapshotVersion.FindAllStringSubmatch(v.v.Version, -1)
+	if len(s) != 1 || len(s[0]) < 4 {
+		return hash, errkind.New(ErrNotSnapshot, "the dchversion %s is not a valid snapshot", v.v.String())
+	}
+	return s[0][3], nil
+}
+
 func SetSnapshotRelease(old Version, r int) (v Version, err error) {
 	s := regExSplitSnapshotVersion.FindAllStringSubmatch(old.v.Version, -1)
-	if len(s) != 1 && len(s[0]) < 4 {
-		return v, fmt.Errorf("the dchversion %s is not a valid snapshot", v.v.String())
+	if len(s) != 1 || len(s[0]) < 4 {
+		return v, errkind.New(ErrNotSnapshot, "the dchversion %s is not a valid snapshot", old.v.String())
 	}
 
 	v.v.Version = s[0][1] + "~" + strconv.Itoa(r) + ".gbp" + s[0][3]
@@ -343,12 +355,12 @@ func SetSnapshotRelease(old Version, r int) (v Version, err error) {
 
 func CompareSnapshots(a Version, b Version) (r int, err error) {
 	sOld := regExSplitSnapshotVersion.FindAllStringSubmatch(a.v.Version, -1)
-	if len(sOld) != 1 && len(sOld[0]) < 4 {
-		return r, fmt.Errorf("the dchversion %s is not a valid snapshot", a.v.String())
+	if len(sOld) != 1 || len(sOld[0]) < 4 {
+		return r, errkind.New(ErrNotSnapshot, "the dchversion %s is not a valid snapshot", a.v.String())
 	}
 	sNew := regExSplitSnapshotVersion.FindAllStringSubmatch(b.v.Version, -1)
-	if len(sNew) != 1 && len(sNew[0]) < 4 {
-		return r, fmt.Errorf("the dchversion %s is not a valid snapshot", b.v.String())
+	if len(sNew) != 1 || len(sNew[0]) < 4 {
+		return r, errkind.New(ErrNotSnapshot, "the dchversion %s is not a valid snapshot", b.v.String())
 	}
 
 	a.v.Version = sOld[0][1] + "~" + sOld[0][2] + ".gbp"
@@ -356,3 +368,42 @@ func CompareSnapshots(a Version, b Version) (r int, err error) {
 
 	return version.Compare(a.v, b.v), nil
 }
+
+// RPM converts the version to the Version and Release fields of an RPM
+// package. The staging and snapshot versions, which sort before the release
+// using a tilde, get a "0.N.tag" release, as in the Fedora guidelines for
+// the pre-release packages; the characters not accepted by RPM are replaced.
+func (v Version) RPM() (rpmVersion, rpmRelease string) {
+	upstream, revision := v.v.Version, v.v.Revision
+
+	switch {
+	case v.IsSnapshot():
+		s := regExSplitSnapshotVersion.FindAllStringSubmatch(upstream, -1)
+		upstream, revision = s[0][1], "0."+s[0][2]+".git"+s[0][3]
+	case v.IsStaging():
+		s := regExSplitStagingVersion.FindAllStringSubmatch(upstream, -1)
+		upstream, revision = s[0][1], "0."+revision+".stg"
+	case strings.Contains(upstream, "~"):
+		parts := strings.SplitN(upstream, "~", 2)
+		if revision == "" {
+			revision = "1"
+		}
+		upstream, revision = parts[0], "0."+revision+"."+parts[1]
+	case revision == "":
+		revision = "1"
+	}
+
+	replacer := strings.NewReplacer("-", "_", "~", ".", ":", "_")
+	return replacer.Replace(upstream), replacer.Replace(revision)
+}
+
+// RPMString returns the version in the "[epoch:]version-release" format
+// of the RPM changelogs
+func (v Version) RPMString() string {
+	rpmVersion, rpmRelease := v.RPM()
+	if v.v.Epoch > 0 {
+		rpmVersion = strconv.FormatUint(uint64(v.v.Epoch), 10) + ":" + rpmVersion
+	}
+
+	return rpmVersion + "-" + rpmRelease
+}
@@ -16,6 +16,7 @@
 package dchversion
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -865,3 +866,75 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestRPMString(t *testing.T) {
+	tests := []struct {
+		name string
+		v    Version
+		want string
+	}{
+		{name: `stable`, v: NewVersion(0, "1.7.0", "1"), want: "1.7.0-1"},
+		{name: `epoch`, v: NewVersion(2, "1.7.0", "3"), want: "2:1.7.0-3"},
+		{name: `native`, v: NewVersion(0, "1.7.0", ""), want: "1.7.0-1"},
+		{name: `staging`, v: NewVersion(0, "1.7.0~stg", "2"), want: "1.7.0-0.2.stg"},
+		{name: `development`, v: NewVersion(0, "1.7.0.20180214", "1"), want: "1.7.0.20180214-1"},
+		{name: `snapshot`, v: NewVersion(0, "1.7.0~3.gbp1a2b3c", ""), want: "1.7.0-0.3.git1a2b3c"},
+		{name: `tilde`, v: NewVersion(0, "2.0~rc1", "1"), want: "2.0-0.1.rc1"},
+		{name: `backport`, v: NewVersion(0, "1.0-beta", "1~bpo9+1"), want: "1.0_beta-1.bpo9+1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.RPMString(); got != tt.want {
+				t.Errorf("RPMString() = '%v', want '%v'", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSnapshotHash(t *testing.T) {
+	tests := []struct {
+		name      string
+		v         Version
+		want      string
+		wantError bool
+	}{
+		{name: `snapshot`, v: NewVersion(0, "1.7.0~3.gbp1a2b3c", ""), want: "1a2b3c"},
+		{name: `epoch`, v: NewVersion(1, "1.7.0~12.gbpabcdef", ""), want: "abcdef"},
+		{name: `stable`, v: NewVersion(0, "1.7.0", "1"), wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSnapshotHash(tt.v)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("GetSnapshotHash() error = %v, wantError %v", err, tt.wantError)
+			}
+			if got != tt.want {
+				t.Errorf("GetSnapshotHash() = '%v', want '%v'", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	snapshot := NewVersion(0, "1.7.0~1.gbp1a2b3c", "")
+	tests := []struct {
+		name string
+		run  func() error
+		want error
+	}{
+		{name: `parse`, run: func() error { _, err := Parse("1.0:a"); return err }, want: ErrInvalidVersion},
+		{name: `compare`, run: func() error { _, err := CompareStrings("1.0", "a:1"); return err }, want: ErrInvalidVersion},
+		{name: `build`, run: func() error { _, err := NewVersion(0, "1.7.0~stg", "1").Build(Development); return err }, want: ErrIncompatibleVersion},
+		{name: `snapshot`, run: func() error { _, err := GetSnapshotHash(NewVersion(0, "1.7.0", "1")); return err }, want: ErrNotSnapshot},
+		{name: `snapshotRelease`, run: func() error { _, err := GetSnapshotRelease(NewVersion(0, "1.7.0", "1")); return err }, want: ErrNotSnapshot},
+		{name: `setSnapshotRelease`, run: func() error { _, err := SetSnapshotRelease(NewVersion(0, "1.7.0", "1"), 2); return err }, want: ErrNotSnapshot},
+		{name: `compareSnapshots`, run: func() error { _, err := CompareSnapshots(snapshot, NewVersion(0, "1.7.0", "1")); return err }, want: ErrNotSnapshot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -16,9 +16,14 @@
 package git
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/cinello/git-dch/internal/errkind"
+	"github.com/cinello/git-dch/pkg/logging"
+
 	"github.com/cinello/go-debian/version"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
@@ -30,6 +35,7 @@ type searchCommitsData struct {
 	head            *plumbing.Reference
 	conditionBefore func(c *object.Commit) bool
 	conditionAfter  func(c *object.Commit) bool
+	accept          func(c *object.Commit) bool
 	ignoreMerges    bool
 }
 
@@ -45,8 +51,10 @@ func searchCommitsToCondition(data searchCommitsData) (commits []*object.Commit)
 			headFound = true
 		}
 
-		// Ignore merge commits
-		if data.ignoreMerges && len(c.ParentHashes) > 1 {
+		// Ignore merge commits and the ones rejected by the filter
+		isIgnoredMerge := data.ignoreMerges && len(c.ParentHashes) > 1
+		isFiltered := data.accept != nil && !data.accept(c)
+		if isIgnoredMerge || isFiltered {
 			if data.conditionAfter != nil && data.conditionAfter(c) {
 				break
 			}
@@ -65,6 +73,21 @@ func searchCommitsToCondition(data searchCommitsData) (commits []*object.Commit)
 	return
 }
 
+func (gr *Repository) acceptCommit(c *object.Commit) bool {
+
+	if gr.filter.IsEmpty() {
+		return true
+	}
+
+	accepted := gr.filter.Accept(c, gr.CommitAuthor(c))
+	if !accepted {
+		gr.logger.Debug("commit skipped by the filter",
+			logging.F("commit", c.Hash.String()[0:7]), logging.F("subject", commitSubject(c.Message)))
+	}
+
+	return accepted
+}
+
 func (gr *Repository) sortedCommits() (commits []*object.Commit, head *plumbing.Reference, err error) {
 
 	var (
@@ -104,43 +127,169 @@ func (gr *Repository) CommitsToTime(t time.Time, ignoreMerges bool) (commits []*
 	data.conditionBefore = func(c *object.Commit) bool {
 		return c.Author.When.Before(t)
 	}
+	data.accept = gr.acceptCommit
 	data.ignoreMerges = ignoreMerges
 	if err == nil {
-		commits = searchCommitsToCondition(data)
+		commits, err = gr.netChanges(searchCommitsToCondition(data))
 	}
+	gr.logger.Debug("commits selected", logging.F("since", t.Format(time.RFC3339)), logging.F("count", len(commits)))
 
 	return
 }
 
+// CommitsToCommit returns the commits from HEAD back to the given commit,
+// which can be a tag, a reference, a hash or a revision (see ResolveRevision).
+// A range A..B selects the commits reachable from B and not from A, as
+// CommitsBetween.
 func (gr *Repository) CommitsToCommit(commit string, ignoreMerges bool) (commits []*object.Commit, err error) {
 
-	// Find the commit hash for the passed reference
-	var commitFromReference string
-	commitFromReference = gr.CommitAtTag(version.Version{Version: commit})
-	if commitFromReference == "" {
-		commitFromReference = gr.CommitAtTagObject(version.Version{Version: commit})
+	if strings.Contains(commit, "...") {
+		return nil, errkind.New(ErrInvalidRevision, textUnsupportedRange, commit)
 	}
-	if commitFromReference == "" {
-		commitFromReference = gr.CommitAtReference(commit)
+	if from, to, ok := SplitRange(commit); ok {
+		if from, err = gr.ResolveRevision(from); err != nil {
+			return
+		}
+		if to, err = gr.ResolveRevision(to); err != nil {
+			return
+		}
+		gr.logger.Debug("commit range resolved", logging.F("range", commit), logging.F("from", from), logging.F("to", to))
+		return gr.CommitsBetween(from, to, ignoreMerges)
 	}
-	// If a commit was not found the passed value was a commit hash itself or an empty value
-	if commitFromReference != "" {
-		commit = commitFromReference
+
+	var hash string
+	if hash, err = gr.startCommit(commit); err != nil {
+		return
+	}
+	if hash != "" {
+		if _, err = gr.repository.CommitObject(plumbing.NewHash(hash)); err != nil {
+			gr.logger.Warning("the start commit does not exist, the whole history is used", logging.F("since", commit))
+			err = nil
+		}
 	}
 
+	return gr.commitsToHash(hash, ignoreMerges)
+}
+
+// commitsToHash returns the commits from HEAD back to the commit with the
+// given full hash, or the whole history if it is not found
+func (gr *Repository) commitsToHash(hash string, ignoreMerges bool) (commits []*object.Commit, err error) {
+
 	var (
 		data searchCommitsData
 	)
 
 	data.commits, data.head, err = gr.sortedCommits()
 	data.conditionAfter = func(c *object.Commit) bool {
-		return c.Hash.String() == commit
+		return c.Hash.String() == hash
 	}
+	data.accept = gr.acceptCommit
 	data.ignoreMerges = ignoreMerges
 
 	if err == nil {
-		commits = searchCommitsToCondition(data)
+		commits, err = gr.netChanges(searchCommitsToCondition(data))
+	}
+	gr.logger.Debug("commits selected", logging.F("since", hash), logging.F("count", len(commits)))
+
+	return
+}
+
+// startCommit returns the full hash, in lower case, of the start commit given
+// as a tag, a reference, a revision or a hash; it is empty for an empty value
+func (gr *Repository) startCommit(commit string) (hash string, err error) {
+
+	if hash, err = gr.commitFromReference(commit); err != nil {
+		return
+	}
+	// If a commit was not found the passed value was a commit hash itself or an empty value
+	if hash == "" {
+		return strings.ToLower(commit), nil
 	}
+	gr.logger.Debug("start commit resolved", logging.F("since", commit), logging.F("commit", hash))
 
 	return
 }
+
+// commitFromReference returns the commit hash for a tag, a reference or a
+// revision; it is empty for a full hash or an empty value
+func (gr *Repository) commitFromReference(commit string) (hash string, err error) {
+
+	hash = gr.CommitAtTag(version.Version{Version: commit})
+	if hash == "" {
+		hash = gr.CommitAtTagObject(version.Version{Version: commit})
+	}
+	if hash == "" {
+		hash = gr.CommitAtReference(commit)
+	}
+	if hash != "" {
+		// an annotated tag reference points to the tag object
+		return gr.peelTag(hash)
+	}
+
+	// Any other value, but a full hash, is a revision like HEAD~3 or an abbreviated hash
+	if commit != "" && !isFullHash(commit) {
+		return gr.ResolveRevision(commit)
+	}
+
+	return "", nil
+}
+
+// StartCommitError is returned by CommitsToCommitStrict when the start commit
+// does not exist or is not an ancestor of HEAD
+type StartCommitError struct {
+	// Commit is the start commit, as it was given
+	Commit string
+	// NotAncestor is true if the commit exists, but it is not an ancestor of HEAD
+	NotAncestor bool
+}
+
+func (e *StartCommitError) Error() string {
+
+	if e.NotAncestor {
+		return fmt.Sprintf(textCommitIsNotAncestor, e.Commit)
+	}
+
+	return fmt.Sprintf(textCommitNotFound, e.Commit)
+}
+
+// Is matches ErrNotAncestor or ErrCommitNotFound, as NotAncestor
+func (e *StartCommitError) Is(target error) bool {
+
+	if e.NotAncestor {
+		return target == ErrNotAncestor
+	}
+
+	return target == ErrCommitNotFound
+}
+
+// CommitsToCommitStrict returns the same commits as CommitsToCommit, but it
+// fails with a *StartCommitError if the commit does not exist or is not an
+// ancestor of HEAD, instead of returning the whole history
+func (gr *Repository) CommitsToCommitStrict(commit string, ignoreMerges bool) (commits []*object.Commit, err error) {
+
+	if _, _, isRange := SplitRange(commit); commit == "" || isRange || strings.Contains(commit, "...") {
+		return gr.CommitsToCommit(commit, ignoreMerges)
+	}
+
+	var hash string
+	if hash, err = gr.startCommit(commit); err != nil {
+		return
+	}
+	if _, err = gr.repository.CommitObject(plumbing.NewHash(hash)); err != nil {
+		return nil, &StartCommitError{Commit: commit}
+	}
+
+	var head *plumbing.Reference
+	if head, err = gr.repository.Head(); err != nil {
+		return nil, fmt.Errorf(textCannotGetHead, err)
+	}
+	var set map[plumbing.Hash]*object.Commit
+	if set, err = gr.ancestors(head.Hash().String()); err != nil {
+		return
+	}
+	if _, ok := set[plumbing.NewHash(hash)]; !ok {
+		return nil, &StartCommitError{Commit: commit, NotAncestor: true}
+	}
+
+	return gr.commitsToHash(hash, ignoreMerges)
+}
@@ -16,11 +16,24 @@
 package git
 
 import (
-	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cinello/git-dch/internal/errkind"
 
 	"gopkg.in/src-d/go-git.v4/config"
+	format "gopkg.in/src-d/go-git.v4/plumbing/format/config"
+)
+
+const (
+	// maxConfigIncludeDepth limits the include chains, as git does, to avoid loops
+	maxConfigIncludeDepth = 10
 )
 
+// ConfigValue returns the value of a key from the repository local configuration only
 func (gr *Repository) ConfigValue(section, key string) (value string, err error) {
 
 	var (
@@ -28,8 +41,253 @@ func (gr *Repository) ConfigValue(section, key string) (value string, err error)
 	)
 
 	if c, err = gr.repository.Config(); err != nil {
-		return "", fmt.Errorf(textCannotGetConfigurationValue, err)
+		return "", errkind.New(ErrConfig, textCannotGetConfigurationValue, err)
 	}
 
 	return c.Raw.Section(section).Option(key), nil
 }
+
+// ConfigSubsectionValue returns the value of a key in a subsection of the
+// repository local configuration, like branch.<name>.remote
+func (gr *Repository) ConfigSubsectionValue(section, subsection, key string) (value string, err error) {
+
+	var (
+		c *config.Config
+	)
+
+	if c, err = gr.repository.Config(); err != nil {
+		return "", errkind.New(ErrConfig, textCannotGetConfigurationValue, err)
+	}
+
+	return c.Raw.Section(section).Subsection(subsection).Option(key), nil
+}
+
+// SetConfigSubsectionValue sets the value of a key in a subsection of the
+// repository local configuration, like merge.<name>.driver
+func (gr *Repository) SetConfigSubsectionValue(section, subsection, key, value string) (err error) {
+
+	var (
+		c *config.Config
+	)
+
+	if c, err = gr.repository.Config(); err != nil {
+		return errkind.New(ErrConfig, textCannotSetConfigurationValue, err)
+	}
+
+	c.Raw.Section(section).Subsection(subsection).SetOption(key, value)
+	if err = gr.repository.Storer.SetConfig(c); err != nil {
+		return errkind.New(ErrConfig, textCannotSetConfigurationValue, err)
+	}
+
+	return nil
+}
+
+// EffectiveConfigValue returns the value of a key as git itself would resolve it:
+// the system, global and local configuration files are read in this order,
+// following include and includeIf directives, and the last definition wins.
+// The found return value is false if the key is not defined at any level.
+func (gr *Repository) EffectiveConfigValue(section, key string) (value string, found bool, err error) {
+
+	for _, file := range gr.configFiles() {
+		var v string
+		var ok bool
+		if v, ok, err = gr.configFileValue(file, section, key, 0); err != nil {
+			return "", false, errkind.New(ErrConfig, textCannotGetConfigurationValue, err)
+		}
+		if ok {
+			value, found = v, true
+		}
+	}
+
+	return
+}
+
+// configFiles returns the list of the configuration files read by git, from
+// the lowest to the highest priority
+func (gr *Repository) configFiles() (files []string) {
+
+	if os.Getenv("GIT_CONFIG_NOSYSTEM") == "" {
+		system := os.Getenv("GIT_CONFIG_SYSTEM")
+		if system == "" {
+			system = "/etc/gitconfig"
+		}
+		files = append(files, system)
+	}
+
+	if global := os.Getenv("GIT_CONFIG_GLOBAL"); global != "" {
+		files = append(files, global)
+	} else {
+		xdg := os.Getenv("XDG_CONFIG_HOME")
+		if xdg == "" {
+			xdg = expandHome("~/.config")
+		}
+		files = append(files, filepath.Join(xdg, "git", "config"), expandHome("~/.gitconfig"))
+	}
+
+	return append(files, filepath.Join(gr.gitDir(), "config"))
+}
+
+// configFileValue reads a key from a single configuration file, recursively
+// following its include directives. Missing files are silently ignored, as
+// the included files which cannot be read, as git does.
+func (gr *Repository) configFileValue(path, section, key string, depth int) (value string, found bool, err error) {
+
+	if depth > maxConfigIncludeDepth {
+		return "", false, errkind.New(ErrConfig, "exceeded maximum include depth while reading %s", path)
+	}
+
+	var data []byte
+	if data, err = ioutil.ReadFile(path); err != nil {
+		if os.IsNotExist(err) || os.IsPermission(err) || depth > 0 {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	c := format.New()
+	if err = format.NewDecoder(strings.NewReader(string(data))).Decode(c); err != nil {
+		return "", false, errkind.New(ErrConfig, "cannot parse %s: %s", path, err)
+	}
+
+	if options := c.Section(section).Options; hasOption(options, key) {
+		value, found = options.Get(key), true
+	}
+
+	// Included files are processed after the values of the including file
+	includes := c.Section("include").Options.GetAll("path")
+	for _, s := range c.Section("includeIf").Subsections {
+		if gr.includeConditionMatches(s.Name, path) {
+			includes = append(includes, s.Options.GetAll("path")...)
+		}
+	}
+
+	for _, include := range includes {
+		if include == "" {
+			continue
+		}
+		include = expandHome(include)
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+
+		var v string
+		var ok bool
+		if v, ok, err = gr.configFileValue(include, section, key, depth+1); err != nil {
+			return "", false, err
+		}
+		if ok {
+			value, found = v, true
+		}
+	}
+
+	return
+}
+
+// includeConditionMatches evaluates the condition of an includeIf section.
+// The gitdir, gitdir/i and onbranch conditions are supported.
+func (gr *Repository) includeConditionMatches(condition, configPath string) bool {
+
+	switch {
+	case strings.HasPrefix(condition, "gitdir:"):
+		return matchGitDir(strings.TrimPrefix(condition, "gitdir:"), gr.gitDir(), configPath, false)
+	case strings.HasPrefix(condition, "gitdir/i:"):
+		return matchGitDir(strings.TrimPrefix(condition, "gitdir/i:"), gr.gitDir(), configPath, true)
+	case strings.HasPrefix(condition, "onbranch:"):
+		branch, err := gr.ActiveBranch()
+		if err != nil {
+			return false
+		}
+		pattern := strings.TrimPrefix(condition, "onbranch:")
+		if strings.HasSuffix(pattern, "/") {
+			pattern += "**"
+		}
+		return globToRegexp(pattern, false).MatchString(branch)
+	}
+
+	return false
+}
+
+// matchGitDir applies the gitdir pattern rules described in git-config(1)
+func matchGitDir(pattern, gitDir, configPath string, insensitive bool) bool {
+
+	directory := strings.HasSuffix(pattern, "/")
+
+	pattern = expandHome(pattern)
+	if strings.HasPrefix(pattern, "./") {
+		pattern = filepath.Join(filepath.Dir(configPath), pattern[2:])
+	}
+	pattern = filepath.ToSlash(pattern)
+	if !strings.HasPrefix(pattern, "/") && !strings.HasPrefix(pattern, "**/") {
+		pattern = "**/" + pattern
+	}
+	if directory {
+		pattern = strings.TrimSuffix(pattern, "/") + "/**"
+	}
+
+	dir := filepath.ToSlash(gitDir)
+	if resolved, err := filepath.EvalSymlinks(gitDir); err == nil {
+		resolved = filepath.ToSlash(resolved)
+		if resolved != dir && globToRegexp(pattern, insensitive).MatchString(resolved) {
+			return true
+		}
+	}
+
+	return globToRegexp(pattern, insensitive).MatchString(dir)
+}
+
+// globToRegexp converts a wildmatch pattern, where ** crosses directories, to a regular expression
+func globToRegexp(pattern string, insensitive bool) *regexp.Regexp {
+
+	var expr strings.Builder
+	if insensitive {
+		expr.WriteString("(?i)")
+	}
+	expr.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+
+	return regexp.MustCompile(expr.String())
+}
+
+// hasOption reports if a key is defined in the options, even with an empty value
+func hasOption(options format.Options, key string) bool {
+
+	for _, o := range options {
+		if o.IsKey(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expandHome replaces a leading ~/ with the user home directory
+func expandHome(path string) string {
+
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home := os.Getenv("HOME")
+	if home == "" {
+		return path
+	}
+
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,108 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/src-d/go-git.v4"
+)
+
+func TestEffectiveConfigValue(t *testing.T) {
+
+	home, err := ioutil.TempDir("", "git-dch-config")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(home)
+
+	for _, env := range []string{"HOME", "XDG_CONFIG_HOME", "GIT_CONFIG_NOSYSTEM", "GIT_CONFIG_GLOBAL"} {
+		defer os.Setenv(env, os.Getenv(env))
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	os.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	os.Unsetenv("GIT_CONFIG_GLOBAL")
+
+	work := filepath.Join(home, "work", "project")
+	other := filepath.Join(home, "other")
+	for _, path := range []string{work, other} {
+		if _, err := git.PlainInit(path, false); err != nil {
+			t.Fatalf("cannot create repository %s: %s", path, err)
+		}
+	}
+
+	// an include which cannot be read is ignored, as git does
+	if err := os.Mkdir(filepath.Join(home, "unreadable"), 0755); err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+
+	files := map[string]string{
+		".gitconfig": "[user]\n\tname = Global Name\n\temail = global@mail.tld\n" +
+			"[include]\n\tpath = .gitconfig-common\n" +
+			"[includeIf \"gitdir:~/work/\"]\n\tpath = ~/.gitconfig-work\n",
+		".gitconfig-common": "[core]\n\tpager = less\n" +
+			"[include]\n\tpath = unreadable\n",
+		".gitconfig-work": "[user]\n\temail = work@mail.tld\n",
+		"work/project/.git/config": "[core]\n\tbare = false\n" +
+			"[user]\n\tsigningkey = ABCDEF\n",
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(home, filepath.FromSlash(name)), []byte(contents), 0644); err != nil {
+			t.Fatalf("cannot write %s: %s", name, err)
+		}
+	}
+
+	type args struct {
+		repository string
+		section    string
+		key        string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      string
+		wantFound bool
+	}{
+		{name: `global`, args: args{repository: other, section: "user", key: "name"}, want: "Global Name", wantFound: true},
+		{name: `globalEmail`, args: args{repository: other, section: "user", key: "email"}, want: "global@mail.tld", wantFound: true},
+		{name: `includeIf`, args: args{repository: work, section: "user", key: "email"}, want: "work@mail.tld", wantFound: true},
+		{name: `include`, args: args{repository: other, section: "core", key: "pager"}, want: "less", wantFound: true},
+		{name: `local`, args: args{repository: work, section: "user", key: "signingkey"}, want: "ABCDEF", wantFound: true},
+		{name: `notLocal`, args: args{repository: other, section: "user", key: "signingkey"}, wantFound: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gr, err := NewRepository(tt.args.repository)
+			if err != nil {
+				t.Fatalf("cannot open repository: %s", err)
+			}
+
+			got, found, err := gr.EffectiveConfigValue(tt.args.section, tt.args.key)
+			if err != nil {
+				t.Errorf("cannot get configuration value: %s", err)
+				return
+			}
+
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("EffectiveConfigValue(%v) = '%v', %v, want '%v', %v", tt.args, got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNoRepository is returned when the git repository cannot be opened
+	ErrNoRepository = errors.New("git repository not found")
+	// ErrNoBranch is returned when HEAD is not on a branch
+	ErrNoBranch = errors.New("HEAD is not on a branch")
+	// ErrCommitNotFound is returned when a commit does not exist
+	ErrCommitNotFound = errors.New("commit not found")
+	// ErrNotAncestor is returned when a commit is not an ancestor of HEAD
+	ErrNotAncestor = errors.New("commit is not an ancestor of HEAD")
+	// ErrAmbiguousHash is returned when an abbreviated hash matches more than one commit
+	ErrAmbiguousHash = errors.New("ambiguous abbreviated hash")
+	// ErrInvalidRevision is returned when a revision cannot be parsed or resolved
+	ErrInvalidRevision = errors.New("invalid revision")
+	// ErrNoUpstream is returned when a branch has no upstream branch
+	ErrNoUpstream = errors.New("no upstream branch")
+	// ErrInvalidFilter is returned when a commit filter rule is not valid
+	ErrInvalidFilter = errors.New("invalid commit filter")
+	// ErrConfig is returned when the git configuration cannot be read or written
+	ErrConfig = errors.New("git configuration error")
+)
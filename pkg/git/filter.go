@@ -0,0 +1,230 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cinello/git-dch/internal/errkind"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+var (
+	regExTrailer = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*)\s*:\s*(.*)$`)
+)
+
+// Trailer is a "Key: value" line in the last paragraph of a commit message
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// TrailerMatcher matches the trailers with the given key (case insensitive)
+// and a value matching the whole regular expression (case insensitive).
+// A nil Value matches any value.
+type TrailerMatcher struct {
+	Key   string
+	Value *regexp.Regexp
+}
+
+// Filter selects the commits reported in the logs. A commit is accepted if,
+// for each kind of include rule defined, it matches at least one of them,
+// and it doesn't match any exclude rule. Subjects and authors are matched
+// against regular expressions; authors in the "Name <email>" format,
+// after the mailmap translation.
+type Filter struct {
+	IncludeSubjects []*regexp.Regexp
+	ExcludeSubjects []*regexp.Regexp
+	IncludeAuthors  []*regexp.Regexp
+	ExcludeAuthors  []*regexp.Regexp
+	IncludeTrailers []TrailerMatcher
+	ExcludeTrailers []TrailerMatcher
+}
+
+// NewFilter creates a Filter from the text representation of its rules.
+// Trailer rules are in the "Key: value-regexp" or "Key" format.
+func NewFilter(
+	includeSubjects, excludeSubjects,
+	includeAuthors, excludeAuthors,
+	includeTrailers, excludeTrailers []string,
+) (f Filter, err error) {
+
+	if f.IncludeSubjects, err = compileRegexps(includeSubjects); err != nil {
+		return
+	}
+	if f.ExcludeSubjects, err = compileRegexps(excludeSubjects); err != nil {
+		return
+	}
+	if f.IncludeAuthors, err = compileRegexps(includeAuthors); err != nil {
+		return
+	}
+	if f.ExcludeAuthors, err = compileRegexps(excludeAuthors); err != nil {
+		return
+	}
+	if f.IncludeTrailers, err = compileTrailerMatchers(includeTrailers); err != nil {
+		return
+	}
+	f.ExcludeTrailers, err = compileTrailerMatchers(excludeTrailers)
+
+	return
+}
+
+func compileRegexps(values []string) (list []*regexp.Regexp, err error) {
+
+	for _, v := range values {
+		var r *regexp.Regexp
+		if r, err = regexp.Compile(v); err != nil {
+			return nil, errkind.New(ErrInvalidFilter, textInvalidFilterExpression, v, err)
+		}
+		list = append(list, r)
+	}
+
+	return
+}
+
+func compileTrailerMatchers(values []string) (list []TrailerMatcher, err error) {
+
+	for _, v := range values {
+		var m TrailerMatcher
+		if m, err = ParseTrailerMatcher(v); err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+
+	return
+}
+
+// ParseTrailerMatcher parses a trailer rule in the "Key: value-regexp" or "Key" format
+func ParseTrailerMatcher(value string) (m TrailerMatcher, err error) {
+
+	parts := strings.SplitN(value, ":", 2)
+	m.Key = strings.TrimSpace(parts[0])
+	if m.Key == "" || strings.Contains(m.Key, " ") {
+		return m, errkind.New(ErrInvalidFilter, textInvalidTrailerRule, value)
+	}
+
+	if len(parts) == 2 && strings.TrimSpace(parts[1]) != "" {
+		expression := strings.TrimSpace(parts[1])
+		if m.Value, err = regexp.Compile(`(?i)^(?:` + expression + `)$`); err != nil {
+			return m, errkind.New(ErrInvalidFilter, textInvalidFilterExpression, expression, err)
+		}
+	}
+
+	return
+}
+
+// Matches reports if the matcher accepts at least one of the trailers
+func (m TrailerMatcher) Matches(trailers []Trailer) bool {
+
+	for _, t := range trailers {
+		if strings.EqualFold(t.Key, m.Key) && (m.Value == nil || m.Value.MatchString(t.Value)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CommitTrailers returns the trailers of a commit message, that is the
+// "Key: value" lines of its last paragraph, when it's not the subject
+func CommitTrailers(message string) (trailers []Trailer) {
+
+	paragraphs := strings.Split(strings.TrimSpace(strings.Replace(message, "\r\n", "\n", -1)), "\n\n")
+	if len(paragraphs) < 2 {
+		return nil
+	}
+
+	for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+		if values := regExTrailer.FindStringSubmatch(strings.TrimSpace(line)); values != nil {
+			trailers = append(trailers, Trailer{Key: values[1], Value: strings.TrimSpace(values[2])})
+		}
+	}
+
+	return
+}
+
+// commitSubject returns the first line of a commit message
+func commitSubject(message string) string {
+
+	return strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+}
+
+// IsEmpty returns true if the filter has no rules
+func (f Filter) IsEmpty() bool {
+
+	return len(f.IncludeSubjects) == 0 && len(f.ExcludeSubjects) == 0 &&
+		len(f.IncludeAuthors) == 0 && len(f.ExcludeAuthors) == 0 &&
+		len(f.IncludeTrailers) == 0 && len(f.ExcludeTrailers) == 0
+}
+
+// Accept reports if a commit, whose author is given already translated
+// through the mailmap, passes the filter rules
+func (f Filter) Accept(c *object.Commit, author object.Signature) bool {
+
+	subject := commitSubject(c.Message)
+	name := author.Name + " <" + author.Email + ">"
+	trailers := CommitTrailers(c.Message)
+
+	if anyRegexpMatches(f.ExcludeSubjects, subject) || anyRegexpMatches(f.ExcludeAuthors, name) {
+		return false
+	}
+	for _, m := range f.ExcludeTrailers {
+		if m.Matches(trailers) {
+			return false
+		}
+	}
+
+	if len(f.IncludeSubjects) > 0 && !anyRegexpMatches(f.IncludeSubjects, subject) {
+		return false
+	}
+	if len(f.IncludeAuthors) > 0 && !anyRegexpMatches(f.IncludeAuthors, name) {
+		return false
+	}
+	if len(f.IncludeTrailers) > 0 {
+		found := false
+		for _, m := range f.IncludeTrailers {
+			if m.Matches(trailers) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func anyRegexpMatches(list []*regexp.Regexp, value string) bool {
+
+	for _, r := range list {
+		if r.MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetFilter sets the rules used to select the commits reported in the logs
+func (gr *Repository) SetFilter(f Filter) {
+
+	gr.filter = f
+}
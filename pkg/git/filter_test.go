@@ -0,0 +1,82 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"testing"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestFilterAccept(t *testing.T) {
+
+	var (
+		developer = object.Signature{Name: "Jane Doe", Email: "jane@mail.tld"}
+		bot       = object.Signature{Name: "renovate[bot]", Email: "bot@renovate.tld"}
+	)
+
+	type rules struct {
+		includeSubjects, excludeSubjects []string
+		includeAuthors, excludeAuthors   []string
+		includeTrailers, excludeTrailers []string
+	}
+	tests := []struct {
+		name      string
+		rules     rules
+		message   string
+		author    object.Signature
+		want      bool
+		wantError bool
+	}{
+		{name: `noRules`, message: "Fix bug", author: developer, want: true},
+		{name: `excludeSubject`, rules: rules{excludeSubjects: []string{`^WIP`, `^Merge branch`}}, message: "WIP: half done", author: developer, want: false},
+		{name: `excludeSubjectBody`, rules: rules{excludeSubjects: []string{`^WIP`}}, message: "Fix bug\n\nWIP in body", author: developer, want: true},
+		{name: `excludeAuthor`, rules: rules{excludeAuthors: []string{`\[bot\]`}}, message: "Update deps", author: bot, want: false},
+		{name: `excludeTrailer`, rules: rules{excludeTrailers: []string{`Gbp-Dch: Ignore`}}, message: "Fix bug\n\ngbp-dch: ignore", author: developer, want: false},
+		{name: `excludeTrailerValue`, rules: rules{excludeTrailers: []string{`Gbp-Dch: Ignore`}}, message: "Fix bug\n\nGbp-Dch: Full", author: developer, want: true},
+		{name: `excludeTrailerSubject`, rules: rules{excludeTrailers: []string{`Gbp-Dch`}}, message: "Gbp-Dch: Ignore", author: developer, want: true},
+		{name: `includeSubject`, rules: rules{includeSubjects: []string{`^(Fix|Add)`}}, message: "Refactor code", author: developer, want: false},
+		{name: `includeAuthor`, rules: rules{includeAuthors: []string{`@mail\.tld>$`}}, message: "Fix bug", author: developer, want: true},
+		{name: `includeTrailer`, rules: rules{includeTrailers: []string{`Closes`}}, message: "Fix bug\n\nCloses: #123", author: developer, want: true},
+		{name: `wrongRegexp`, rules: rules{excludeSubjects: []string{`(`}}, wantError: true},
+		{name: `wrongTrailer`, rules: rules{excludeTrailers: []string{`: value`}}, wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFilter(
+				tt.rules.includeSubjects, tt.rules.excludeSubjects,
+				tt.rules.includeAuthors, tt.rules.excludeAuthors,
+				tt.rules.includeTrailers, tt.rules.excludeTrailers,
+			)
+
+			if tt.wantError {
+				if err == nil {
+					t.Error("expected an error, got nothing")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("cannot create filter: %s", err)
+				return
+			}
+
+			got := f.Accept(&object.Commit{Message: tt.message, Author: tt.author}, tt.author)
+			if got != tt.want {
+				t.Errorf("Accept(%q) = '%v', want '%v'", tt.message, got, tt.want)
+			}
+		})
+	}
+}
@@ -17,13 +17,28 @@ package git
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cinello/git-dch/internal/errkind"
+	"github.com/cinello/git-dch/pkg/logging"
 
 	"gopkg.in/src-d/go-git.v4"
 )
 
 type Repository struct {
 	repository *git.Repository
+	path       string
+	mailmap    Mailmap
+	filter     Filter
+
+	squashCherryPicks bool
+	dropReverts       bool
+	wrapWidth         int
+
+	logger *logging.Logger
 }
 
 func NewRepository(path string) (Repository, error) {
@@ -32,9 +47,14 @@ func NewRepository(path string) (Repository, error) {
 	var gr *git.Repository
 
 	if gr, err = git.PlainOpen(path); err != nil {
-		return Repository{}, err
+		return Repository{}, errkind.New(ErrNoRepository, textCannotOpenRepository, path, err)
 	}
-	return Repository{repository: gr}, nil
+	r := Repository{repository: gr, path: path}
+	if r.mailmap, err = r.loadMailmap(); err != nil {
+		return Repository{}, fmt.Errorf(textCannotReadMailmap, err)
+	}
+
+	return r, nil
 }
 
 func NewRepositoryFromCurrentDirectory() (Repository, error) {
@@ -44,8 +64,73 @@ func NewRepositoryFromCurrentDirectory() (Repository, error) {
 
 	path, err = os.Getwd()
 	if err != nil {
-		return Repository{}, fmt.Errorf(textCannotOpenWorkDir, err)
+		return Repository{}, errkind.New(ErrNoRepository, textCannotOpenWorkDir, err)
 	}
 
 	return NewRepository(path)
 }
+
+// FindRepository opens the repository containing the path, which can be a
+// subdirectory of its working tree, searching the parent directories as git
+// does; the repository is opened at the root of its working tree
+func FindRepository(path string) (Repository, error) {
+
+	gr, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return Repository{}, errkind.New(ErrNoRepository, textCannotOpenRepository, path, err)
+	}
+	if w, err := gr.Worktree(); err == nil {
+		path = w.Filesystem.Root()
+	}
+
+	return NewRepository(path)
+}
+
+// WorktreeRoot returns the root directory of the working tree of the repository
+func (gr *Repository) WorktreeRoot() (string, error) {
+
+	w, err := gr.repository.Worktree()
+	if err != nil {
+		return "", errkind.New(ErrNoRepository, textNoWorktree, gr.path, err)
+	}
+
+	return w.Filesystem.Root(), nil
+}
+
+// SetLogger sets the logger explaining which commits are selected, nil
+// disables the logging
+func (gr *Repository) SetLogger(l *logging.Logger) {
+
+	gr.logger = l
+}
+
+// gitDir returns the path of the git directory of the repository, following
+// the "gitdir:" indirection used by worktrees and submodules
+func (gr *Repository) gitDir() string {
+
+	dotGit := filepath.Join(gr.path, ".git")
+
+	info, err := os.Stat(dotGit)
+	switch {
+	case err != nil:
+		// bare repository
+		return gr.path
+	case info.IsDir():
+		return dotGit
+	}
+
+	data, err := ioutil.ReadFile(dotGit)
+	if err != nil {
+		return dotGit
+	}
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return dotGit
+	}
+	dir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(gr.path, dir)
+	}
+
+	return dir
+}
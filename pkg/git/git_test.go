@@ -0,0 +1,70 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindRepository(t *testing.T) {
+
+	path, _ := newTestRepository(t,
+		testCommit{message: "Initial commit", file: "README", contents: "readme\n"},
+	)
+	defer os.RemoveAll(path)
+
+	subdir := filepath.Join(path, "debian", "source")
+	if err := os.MkdirAll(subdir, 0755); err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	outside, err := ioutil.TempDir("", "git-dch-outside")
+	if err != nil {
+		t.Fatalf("cannot create directory: %s", err)
+	}
+	defer os.RemoveAll(outside)
+
+	tests := []struct {
+		name      string
+		path      string
+		wantError error
+	}{
+		{name: `root`, path: path},
+		{name: `subdirectory`, path: subdir},
+		{name: `outside`, path: outside, wantError: ErrNoRepository},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gr, err := FindRepository(tt.path)
+			if !errors.Is(err, tt.wantError) || (err != nil) != (tt.wantError != nil) {
+				t.Fatalf("FindRepository() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil {
+				return
+			}
+			root, err := gr.WorktreeRoot()
+			if err != nil {
+				t.Fatalf("WorktreeRoot() error = %v", err)
+			}
+			if root != path {
+				t.Errorf("WorktreeRoot() = %s, want %s", root, path)
+			}
+		})
+	}
+}
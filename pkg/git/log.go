@@ -17,13 +17,91 @@ package git
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
 )
 
-func buildLogEntryText(c *object.Commit, withAuthor, withHash, withStar, full bool) (out string) {
+var (
+	regExUnbreakableKeyword = regexp.MustCompile(`(?i)^(closes|lp):$`)
+	regExBugNumber          = regexp.MustCompile(`^#?\d+[,;.)]?$`)
+	regExListMarker         = regexp.MustCompile(`^[-*+] +`)
+)
+
+// SetWrapWidth sets the maximum width of the log lines, longer lines are
+// wrapped on spaces. A zero or negative width disables the wrapping.
+func (gr *Repository) SetWrapWidth(width int) {
+
+	gr.wrapWidth = width
+}
+
+// wrapTokens splits a text on spaces, keeping together a "Closes:" keyword
+// and the bug numbers following it
+func wrapTokens(text string) (tokens []string) {
+
+	for _, field := range strings.Fields(text) {
+		n := len(tokens)
+		if n > 0 && regExBugNumber.MatchString(field) {
+			last := strings.Fields(tokens[n-1])
+			if regExUnbreakableKeyword.MatchString(last[0]) && (len(last) == 1 || strings.HasSuffix(last[len(last)-1], ",")) {
+				tokens[n-1] += " " + field
+				continue
+			}
+		}
+		tokens = append(tokens, field)
+	}
+
+	return
+}
+
+// wrapLine formats a text line starting with prefix, wrapping it at the given
+// width; the continuation lines start with indent. Words longer than the width,
+// like URLs, are never broken. The continuation lines of an indented text, or of
+// a list item, are aligned to the start of its text.
+func wrapLine(prefix, indent, text string, width int) string {
+
+	if width <= 0 || utf8.RuneCountInString(prefix+text) <= width {
+		return prefix + text
+	}
+
+	// keep the original indentation of the text on the continuation lines
+	trimmed := strings.TrimLeft(text, " \t")
+	prefix += text[:len(text)-len(trimmed)]
+	indent += text[:len(text)-len(trimmed)]
+
+	// the continuation lines of a list item hang under the text after the marker
+	if marker := regExListMarker.FindString(trimmed); marker != "" {
+		prefix += marker
+		indent += strings.Repeat(" ", len(marker))
+		trimmed = trimmed[len(marker):]
+	}
+
+	out := prefix
+	length := utf8.RuneCountInString(prefix)
+	lineStart := true
+	for _, token := range wrapTokens(trimmed) {
+		size := utf8.RuneCountInString(token)
+		if !lineStart && length+1+size > width {
+			out += "\n" + indent
+			length = utf8.RuneCountInString(indent)
+			lineStart = true
+		}
+		if !lineStart {
+			out += " "
+			length++
+		}
+		out += token
+		length += size
+		lineStart = false
+	}
+
+	return out
+}
+
+func buildLogEntryText(c *object.Commit, author object.Signature, withAuthor, withHash, withStar, full bool, width int) (out string) {
 	lines := strings.Split(c.Message, "\n")
 
 	firstLine := true
@@ -34,25 +112,30 @@ func buildLogEntryText(c *object.Commit, withAuthor, withHash, withStar, full bo
 		}
 
 		// Star
+		var prefix, indent string
+		if withStar {
+			indent = "    "
+		}
 		if withStar && firstLine {
-			out += "  * "
+			prefix = "  * "
 		}
 		if withStar && !firstLine && line != "" {
-			out += "    "
+			prefix = "    "
 		}
 
 		// Text
+		text := line
 		if withHash && firstLine {
-			out += fmt.Sprintf("[%s] %s", c.Hash.String()[0:7], line)
-		} else {
-			out += line
+			text = fmt.Sprintf("[%s] %s", c.Hash.String()[0:7], line)
 		}
 
 		// Author
 		if withAuthor && firstLine {
-			out += " (" + c.Author.Name + ")"
+			text += " (" + author.Name + ")"
 		}
 
+		out += wrapLine(prefix, indent, text, width)
+
 		if firstLine {
 			firstLine = false
 		}
@@ -66,6 +149,17 @@ func buildLogEntryText(c *object.Commit, withAuthor, withHash, withStar, full bo
 	return
 }
 
+// LogCommits returns the log text of a list of commits, formatted as the
+// other Log functions
+func (gr *Repository) LogCommits(list []*object.Commit, withAuthor, withHash, withStar, full bool) (out string) {
+
+	for _, c := range list {
+		out += buildLogEntryText(c, gr.CommitAuthor(c), withAuthor, withHash, withStar, full, gr.wrapWidth)
+	}
+
+	return
+}
+
 func (gr *Repository) Log(withAuthor, withHash, withStar, full, ignoreMerges bool) (out string, err error) {
 
 	var (
@@ -77,7 +171,7 @@ func (gr *Repository) Log(withAuthor, withHash, withStar, full, ignoreMerges boo
 	}
 
 	for _, c := range list {
-		out += buildLogEntryText(c, withAuthor, withHash, withStar, full)
+		out += buildLogEntryText(c, gr.CommitAuthor(c), withAuthor, withHash, withStar, full, gr.wrapWidth)
 	}
 
 	return
@@ -94,7 +188,7 @@ func (gr *Repository) LogToTime(t time.Time, withAuthor, withHash, withStar, ful
 	}
 
 	for _, c := range list {
-		out += buildLogEntryText(c, withAuthor, withHash, withStar, full)
+		out += buildLogEntryText(c, gr.CommitAuthor(c), withAuthor, withHash, withStar, full, gr.wrapWidth)
 	}
 
 	return
@@ -111,7 +205,7 @@ func (gr *Repository) LogToCommit(commit string, withAuthor, withHash, withStar,
 	}
 
 	for _, c := range list {
-		out += buildLogEntryText(c, withAuthor, withHash, withStar, full)
+		out += buildLogEntryText(c, gr.CommitAuthor(c), withAuthor, withHash, withStar, full, gr.wrapWidth)
 	}
 
 	return
@@ -261,3 +261,74 @@ func TestLogEntryText(t *testing.T) {
 		})
 	}
 }
+
+func TestWrapLine(t *testing.T) {
+
+	type args struct {
+		prefix string
+		indent string
+		text   string
+		width  int
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: `short`,
+			args: args{prefix: "  * ", indent: "    ", text: "Fix bug", width: 80},
+			want: "  * Fix bug",
+		},
+		{
+			name: `disabled`,
+			args: args{prefix: "  * ", indent: "    ", text: strings.Repeat("word ", 20), width: 0},
+			want: "  * " + strings.Repeat("word ", 20),
+		},
+		{
+			name: `wrap`,
+			args: args{prefix: "  * ", indent: "    ", text: "Add a very long subject that does not fit in the changelog line width", width: 40},
+			want: "  * Add a very long subject that does\n" +
+				"    not fit in the changelog line width",
+		},
+		{
+			name: `url`,
+			args: args{prefix: "  * ", indent: "    ", text: "See https://bugs.debian.org/cgi-bin/bugreport.cgi?bug=123456 for details", width: 40},
+			want: "  * See\n" +
+				"    https://bugs.debian.org/cgi-bin/bugreport.cgi?bug=123456\n" +
+				"    for details",
+		},
+		{
+			name: `closes`,
+			args: args{prefix: "  * ", indent: "    ", text: "Fix the crash at startup. Closes: #123456, #654321", width: 40},
+			want: "  * Fix the crash at startup.\n" +
+				"    Closes: #123456, #654321",
+		},
+		{
+			name: `bodyIndentation`,
+			args: args{prefix: "    ", indent: "    ", text: "  - nested item with a long description", width: 30},
+			want: "      - nested item with a\n" +
+				"        long description",
+		},
+		{
+			name: `bulletMarker`,
+			args: args{prefix: "    ", indent: "    ", text: "*   starred item with a long description", width: 30},
+			want: "    *   starred item with a\n" +
+				"        long description",
+		},
+		{
+			name: `indentedText`,
+			args: args{prefix: "    ", indent: "    ", text: "  indented text with a long description", width: 30},
+			want: "      indented text with a\n" +
+				"      long description",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapLine(tt.args.prefix, tt.args.indent, tt.args.text, tt.args.width)
+			if got != tt.want {
+				t.Errorf("wrapLine(%v) =\n'%v', want\n'%v'", tt.args, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// Mailmap maps the names and emails recorded in the commits to the
+// canonical ones, as described in gitmailmap(5)
+type Mailmap struct {
+	entries []mailmapEntry
+}
+
+type mailmapEntry struct {
+	properName  string
+	properEmail string
+	commitName  string
+	commitEmail string
+}
+
+// ParseMailmap reads a mailmap from a Reader interface. Lines that cannot be
+// understood are ignored, as git does.
+func ParseMailmap(reader io.Reader) (m Mailmap, err error) {
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if e, ok := parseMailmapLine(line); ok {
+			m.entries = append(m.entries, e)
+		}
+	}
+
+	return m, scanner.Err()
+}
+
+// parseMailmapLine parses one of the following forms:
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+func parseMailmapLine(line string) (e mailmapEntry, ok bool) {
+
+	var names, emails []string
+	for {
+		start := strings.Index(line, "<")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(line[start:], ">")
+		if end < 0 {
+			break
+		}
+		names = append(names, strings.TrimSpace(line[:start]))
+		emails = append(emails, strings.TrimSpace(line[start+1:start+end]))
+		line = line[start+end+1:]
+	}
+
+	switch len(emails) {
+	case 1:
+		e.properName, e.commitEmail = names[0], emails[0]
+	case 2:
+		e.properName, e.properEmail = names[0], emails[0]
+		e.commitName, e.commitEmail = names[1], emails[1]
+	default:
+		return e, false
+	}
+
+	return e, e.commitEmail != "" || e.commitName != ""
+}
+
+// Merge appends the entries of another mailmap, which take precedence
+func (m *Mailmap) Merge(other Mailmap) {
+
+	m.entries = append(m.entries, other.entries...)
+}
+
+// Resolve returns the canonical signature for the given one. Emails are
+// compared case insensitively; entries matching both name and email win
+// over the ones matching only the email, later entries win over earlier ones.
+func (m Mailmap) Resolve(s object.Signature) object.Signature {
+
+	var match *mailmapEntry
+	for i := range m.entries {
+		e := &m.entries[i]
+		if !strings.EqualFold(e.commitEmail, s.Email) {
+			continue
+		}
+		if e.commitName != "" {
+			if strings.EqualFold(e.commitName, s.Name) {
+				match = e
+			}
+			continue
+		}
+		if match == nil || match.commitName == "" {
+			match = e
+		}
+	}
+
+	if match != nil {
+		if match.properName != "" {
+			s.Name = match.properName
+		}
+		if match.properEmail != "" {
+			s.Email = match.properEmail
+		}
+	}
+
+	return s
+}
+
+// loadMailmap reads the .mailmap file at the top of the working tree and
+// the file pointed by the mailmap.file configuration key; a configuration
+// which cannot be read only disables the latter
+func (gr *Repository) loadMailmap() (m Mailmap, err error) {
+
+	files := []string{filepath.Join(gr.path, ".mailmap")}
+
+	// the mailmap is optional, the configuration errors are reported when
+	// a value is really needed
+	file, _, _ := gr.EffectiveConfigValue("mailmap", "file")
+	if file != "" {
+		file = expandHome(file)
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(gr.path, file)
+		}
+		files = append(files, file)
+	}
+
+	for _, path := range files {
+		var f *os.File
+		if f, err = os.Open(path); err != nil {
+			if os.IsNotExist(err) {
+				err = nil
+				continue
+			}
+			return
+		}
+
+		var other Mailmap
+		other, err = ParseMailmap(f)
+		f.Close()
+		if err != nil {
+			return
+		}
+		m.Merge(other)
+	}
+
+	return
+}
+
+// CommitAuthor returns the author of a commit, translated through the repository mailmap
+func (gr *Repository) CommitAuthor(c *object.Commit) object.Signature {
+
+	return gr.mailmap.Resolve(c.Author)
+}
@@ -0,0 +1,86 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestMailmapResolve(t *testing.T) {
+	const (
+		mailmap = `# canonical names
+Yuri Bugelli <yuri@mail.tld>
+<yuri@mail.tld> <yuri@old.tld>
+Jane Doe <jane@mail.tld> <JANE@work.tld>
+Jane Doe <jane@mail.tld> J. Doe <shared@mail.tld>
+not a valid line
+`
+	)
+
+	m, err := ParseMailmap(strings.NewReader(mailmap))
+	if err != nil {
+		t.Fatalf("cannot parse mailmap: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		args object.Signature
+		want object.Signature
+	}{
+		{
+			name: `nameOnly`,
+			args: object.Signature{Name: "yuri", Email: "yuri@mail.tld"},
+			want: object.Signature{Name: "Yuri Bugelli", Email: "yuri@mail.tld"},
+		},
+		{
+			name: `emailOnly`,
+			args: object.Signature{Name: "Yuri B.", Email: "yuri@old.tld"},
+			want: object.Signature{Name: "Yuri B.", Email: "yuri@mail.tld"},
+		},
+		{
+			name: `nameAndEmail`,
+			args: object.Signature{Name: "jdoe", Email: "jane@work.tld"},
+			want: object.Signature{Name: "Jane Doe", Email: "jane@mail.tld"},
+		},
+		{
+			name: `commitName`,
+			args: object.Signature{Name: "J. Doe", Email: "shared@mail.tld"},
+			want: object.Signature{Name: "Jane Doe", Email: "jane@mail.tld"},
+		},
+		{
+			name: `commitNameMismatch`,
+			args: object.Signature{Name: "John Roe", Email: "shared@mail.tld"},
+			want: object.Signature{Name: "John Roe", Email: "shared@mail.tld"},
+		},
+		{
+			name: `unknown`,
+			args: object.Signature{Name: "Other", Email: "other@mail.tld"},
+			want: object.Signature{Name: "Other", Email: "other@mail.tld"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.Resolve(tt.args)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve(%v) = '%v', want '%v'", tt.args, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"unicode"
+
+	fdiff "gopkg.in/src-d/go-git.v4/plumbing/format/diff"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+var (
+	regExRevert = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+)
+
+// SetNetChanges configures the post processing of the commits lists:
+// if squashCherryPicks is true the commits introducing the same changes
+// (same patch id) are reported once, if dropReverts is true the commits
+// reverted in the same range are removed together with their revert.
+func (gr *Repository) SetNetChanges(squashCherryPicks, dropReverts bool) {
+
+	gr.squashCherryPicks = squashCherryPicks
+	gr.dropReverts = dropReverts
+}
+
+// netChanges applies the post processing configured by SetNetChanges to a
+// list of commits, sorted from the newest to the oldest
+func (gr *Repository) netChanges(commits []*object.Commit) (out []*object.Commit, err error) {
+
+	out = commits
+	if gr.dropReverts {
+		out = dropRevertPairs(out)
+	}
+	if gr.squashCherryPicks {
+		out, err = gr.squashByPatchID(out)
+	}
+
+	return
+}
+
+// dropRevertPairs removes the commits reverted in the list, with the reverts themselves
+func dropRevertPairs(commits []*object.Commit) (out []*object.Commit) {
+
+	dropped := make(map[*object.Commit]bool)
+
+	// walk from the newest commit, so a revert of a revert is dropped with the
+	// revert it cancels, and the original commit is kept
+	for i, revert := range commits {
+		values := regExRevert.FindStringSubmatch(revert.Message)
+		if values == nil || dropped[revert] {
+			continue
+		}
+		for j := len(commits) - 1; j > i; j-- {
+			reverted := commits[j]
+			if !dropped[reverted] && strings.HasPrefix(reverted.Hash.String(), values[1]) {
+				dropped[reverted] = true
+				dropped[revert] = true
+				break
+			}
+		}
+	}
+
+	for _, c := range commits {
+		if !dropped[c] {
+			out = append(out, c)
+		}
+	}
+
+	return
+}
+
+// squashByPatchID keeps only the oldest of the commits with the same patch id
+func (gr *Repository) squashByPatchID(commits []*object.Commit) (out []*object.Commit, err error) {
+
+	seen := make(map[string]bool)
+	keep := make([]bool, len(commits))
+	for i := len(commits) - 1; i >= 0; i-- {
+		var id string
+		if id, err = patchID(commits[i]); err != nil {
+			return nil, err
+		}
+		if id == "" || !seen[id] {
+			keep[i] = true
+		}
+		seen[id] = true
+	}
+
+	for i, c := range commits {
+		if keep[i] {
+			out = append(out, c)
+		}
+	}
+
+	return
+}
+
+// patchID computes an identifier of the changes introduced by a commit
+// against its parent, ignoring whitespace and the position of the changes,
+// similar to git patch-id. Merge and root commits have no patch id.
+func patchID(c *object.Commit) (id string, err error) {
+
+	if c.NumParents() != 1 {
+		return "", nil
+	}
+
+	var parent *object.Commit
+	if parent, err = c.Parent(0); err != nil {
+		return
+	}
+
+	var patch *object.Patch
+	if patch, err = parent.Patch(c); err != nil {
+		return
+	}
+
+	h := sha1.New()
+	for _, fp := range patch.FilePatches() {
+		from, to := fp.Files()
+		for _, f := range []fdiff.File{from, to} {
+			if f != nil {
+				h.Write([]byte(f.Path()))
+			}
+			h.Write([]byte{0})
+		}
+
+		if fp.IsBinary() {
+			if to != nil {
+				h.Write([]byte(to.Hash().String()))
+			}
+			continue
+		}
+
+		for _, chunk := range fp.Chunks() {
+			var op byte
+			switch chunk.Type() {
+			case fdiff.Add:
+				op = '+'
+			case fdiff.Delete:
+				op = '-'
+			default:
+				continue
+			}
+			for _, line := range strings.Split(chunk.Content(), "\n") {
+				h.Write([]byte{op})
+				h.Write([]byte(removeSpaces(line)))
+			}
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func removeSpaces(s string) string {
+
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,198 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// testCommit describes a commit created by newTestRepository: the file
+// is written with the given contents, or removed if contents is empty
+type testCommit struct {
+	message  string
+	file     string
+	contents string
+}
+
+// newTestRepository creates a temporary repository with a linear history,
+// one commit per minute, returning its path and the hashes of the commits
+func newTestRepository(t *testing.T, commits ...testCommit) (path string, hashes []string) {
+
+	path, err := ioutil.TempDir("", "git-dch-repository")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %s", err)
+	}
+
+	r, err := git.PlainInit(path, false)
+	if err != nil {
+		t.Fatalf("cannot create repository: %s", err)
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		t.Fatalf("cannot get worktree: %s", err)
+	}
+
+	when := time.Date(2018, 2, 14, 10, 0, 0, 0, time.UTC)
+	for i, c := range commits {
+		message := c.message
+		for j := range hashes {
+			message = replaceHashPlaceholder(message, j, hashes[j])
+		}
+
+		file := filepath.Join(path, c.file)
+		if c.contents == "" {
+			if _, err = w.Remove(c.file); err != nil {
+				t.Fatalf("cannot remove %s: %s", c.file, err)
+			}
+		} else {
+			if err = ioutil.WriteFile(file, []byte(c.contents), 0644); err != nil {
+				t.Fatalf("cannot write %s: %s", file, err)
+			}
+			if _, err = w.Add(c.file); err != nil {
+				t.Fatalf("cannot add %s: %s", c.file, err)
+			}
+		}
+
+		signature := &object.Signature{Name: "Test Author", Email: "test.author@nomail.org", When: when.Add(time.Duration(i) * time.Minute)}
+		var h plumbing.Hash
+		if h, err = w.Commit(message, &git.CommitOptions{Author: signature, Committer: signature}); err != nil {
+			t.Fatalf("cannot commit: %s", err)
+		}
+		hashes = append(hashes, h.String())
+	}
+
+	return path, hashes
+}
+
+// replaceHashPlaceholder replaces {n} in a message with the hash of the n-th commit
+func replaceHashPlaceholder(message string, n int, hash string) string {
+
+	return strings.Replace(message, fmt.Sprintf("{%d}", n), hash, -1)
+}
+
+func TestNetChanges(t *testing.T) {
+
+	path, hashes := newTestRepository(t,
+		testCommit{message: "Initial commit", file: "README", contents: "readme\n"},
+		testCommit{message: "Add feature", file: "feature.go", contents: "package feature\n"},
+		testCommit{message: "Fix typo", file: "README", contents: "README\n"},
+		testCommit{message: "Revert \"Fix typo\"\n\nThis reverts commit {2}.", file: "README", contents: "readme\n"},
+		testCommit{message: "Remove feature", file: "feature.go"},
+		testCommit{message: "Add feature (cherry picked)", file: "feature.go", contents: "package  feature\n"},
+	)
+	defer os.RemoveAll(path)
+
+	tests := []struct {
+		name              string
+		squashCherryPicks bool
+		dropReverts       bool
+		want              []string
+	}{
+		{name: `none`, want: []string{hashes[5], hashes[4], hashes[3], hashes[2], hashes[1], hashes[0]}},
+		{name: `dropReverts`, dropReverts: true, want: []string{hashes[5], hashes[4], hashes[1], hashes[0]}},
+		{name: `squashCherryPicks`, squashCherryPicks: true, want: []string{hashes[4], hashes[3], hashes[2], hashes[1], hashes[0]}},
+		{name: `both`, squashCherryPicks: true, dropReverts: true, want: []string{hashes[4], hashes[1], hashes[0]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gr, err := NewRepository(path)
+			if err != nil {
+				t.Fatalf("cannot open repository: %s", err)
+			}
+			gr.SetNetChanges(tt.squashCherryPicks, tt.dropReverts)
+
+			commits, err := gr.CommitsToCommit("", false)
+			if err != nil {
+				t.Errorf("cannot obtain git log: %s", err)
+				return
+			}
+
+			var got []string
+			for _, c := range commits {
+				got = append(got, c.Hash.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CommitsToCommit() = '%v', want '%v'", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDropRevertPairs(t *testing.T) {
+
+	commit := func(n int, message string) *object.Commit {
+		return &object.Commit{Hash: plumbing.NewHash(fmt.Sprintf("%040x", n)), Message: message}
+	}
+	reverts := func(n int) string {
+		return fmt.Sprintf("Revert\n\nThis reverts commit %040x.", n)
+	}
+
+	tests := []struct {
+		name    string
+		commits []*object.Commit
+		want    []int
+	}{
+		{
+			name:    `none`,
+			commits: []*object.Commit{commit(2, "Second"), commit(1, "First")},
+			want:    []int{2, 1},
+		},
+		{
+			name:    `revert`,
+			commits: []*object.Commit{commit(3, "Third"), commit(2, reverts(1)), commit(1, "First")},
+			want:    []int{3},
+		},
+		{
+			name:    `revertOfRevert`,
+			commits: []*object.Commit{commit(3, reverts(2)), commit(2, reverts(1)), commit(1, "First")},
+			want:    []int{1},
+		},
+		{
+			name:    `revertOfRevertOfRevert`,
+			commits: []*object.Commit{commit(4, reverts(3)), commit(3, reverts(2)), commit(2, reverts(1)), commit(1, "First")},
+			want:    nil,
+		},
+		{
+			name:    `revertOutOfRange`,
+			commits: []*object.Commit{commit(3, reverts(2)), commit(1, "First")},
+			want:    []int{3, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, c := range dropRevertPairs(tt.commits) {
+				var n int
+				fmt.Sscanf(c.Hash.String(), "%x", &n)
+				got = append(got, n)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dropRevertPairs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,8 +18,13 @@ package git
 import (
 	"fmt"
 	"io"
+	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/cinello/git-dch/internal/errkind"
+	"github.com/cinello/git-dch/pkg/logging"
+
 	"github.com/cinello/go-debian/version"
 
 	"gopkg.in/src-d/go-git.v4"
@@ -28,6 +33,64 @@ import (
 	"gopkg.in/src-d/go-git.v4/plumbing/storer"
 )
 
+var (
+	regExAbbreviatedHash = regexp.MustCompile(`^[0-9a-fA-F]{4,40}$`)
+)
+
+// isAbbreviatedHash returns true if the value can be an abbreviated commit hash
+func isAbbreviatedHash(value string) bool {
+
+	return len(value) < 40 && regExAbbreviatedHash.MatchString(value)
+}
+
+// matchingHashes returns the hashes of the commits starting with the given
+// abbreviated hash, sorted
+func (gr *Repository) matchingHashes(abbreviated string) (hashes []string, err error) {
+
+	var i object.CommitIter
+	if i, err = gr.repository.CommitObjects(); err != nil {
+		return
+	}
+	defer i.Close()
+
+	abbreviated = strings.ToLower(abbreviated)
+	err = i.ForEach(func(c *object.Commit) error {
+		if strings.HasPrefix(c.Hash.String(), abbreviated) {
+			hashes = append(hashes, c.Hash.String())
+		}
+		return nil
+	})
+	sort.Strings(hashes)
+
+	return
+}
+
+// ResolveHash returns the full hash of the commit starting with the given
+// abbreviated hash, of at least 4 characters. It fails if no commit, or more
+// than one commit, matches.
+func (gr *Repository) ResolveHash(abbreviated string) (hash string, err error) {
+
+	if !regExAbbreviatedHash.MatchString(abbreviated) {
+		return "", errkind.New(ErrInvalidRevision, textInvalidHash, abbreviated)
+	}
+
+	var hashes []string
+	if hashes, err = gr.matchingHashes(abbreviated); err != nil {
+		return
+	}
+
+	switch len(hashes) {
+	case 0:
+		err = errkind.New(ErrCommitNotFound, textCommitNotFound, abbreviated)
+	case 1:
+		hash = hashes[0]
+	default:
+		err = errkind.New(ErrAmbiguousHash, textAmbiguousHash, abbreviated, strings.Join(hashes, ", "))
+	}
+
+	return
+}
+
 func (gr *Repository) LastCommitHash(l int) (hash string, err error) {
 
 	var (
@@ -174,11 +237,12 @@ func (gr *Repository) ActiveBranch() (branch string, err error) {
 	for {
 		var i *plumbing.Reference
 		if i, err = branches.Next(); err == io.EOF {
-			err = fmt.Errorf(textCommitIsNotValidBranch)
+			err = errkind.New(ErrNoBranch, textCommitIsNotValidBranch)
 			break
 		}
 
 		if head.Name().String() == i.Name().String() {
+			gr.logger.Debug("active branch detected", logging.F("branch", i.Name().Short()))
 			return i.Name().Short(), nil
 		}
 	}
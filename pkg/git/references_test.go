@@ -19,6 +19,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/cinello/go-debian/version"
@@ -153,3 +154,58 @@ func TestCommitAtTagObject(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveHash(t *testing.T) {
+	path, hashes := newTestRepository(t,
+		testCommit{message: "Initial commit", file: "README", contents: "readme\n"},
+		testCommit{message: "Add feature", file: "feature.go", contents: "package feature\n"},
+	)
+	defer os.RemoveAll(path)
+
+	gr, err := NewRepository(path)
+	if err != nil {
+		t.Fatalf("cannot open repository: %s", err)
+	}
+
+	missing := "0000000"
+	for strings.HasPrefix(hashes[0], missing) || strings.HasPrefix(hashes[1], missing) {
+		missing = "1111111"
+	}
+
+	tests := []struct {
+		name      string
+		hash      string
+		want      string
+		wantError bool
+	}{
+		{name: `abbreviated`, hash: hashes[0][:7], want: hashes[0]},
+		{name: `upperCase`, hash: strings.ToUpper(hashes[1][:10]), want: hashes[1]},
+		{name: `full`, hash: hashes[1], want: hashes[1]},
+		{name: `missing`, hash: missing, wantError: true},
+		{name: `short`, hash: hashes[0][:3], wantError: true},
+		{name: `invalid`, hash: "master", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gr.ResolveHash(tt.hash)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ResolveHash() error = %v, wantError %v", err, tt.wantError)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveHash() = '%v', want '%v'", got, tt.want)
+			}
+		})
+	}
+
+	commits, err := gr.CommitsToCommit(hashes[0][:7], false)
+	if err != nil {
+		t.Fatalf("CommitsToCommit() error = %v", err)
+	}
+	var got []string
+	for _, c := range commits {
+		got = append(got, c.Hash.String())
+	}
+	if want := []string{hashes[1], hashes[0]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CommitsToCommit() = %v, want %v", got, want)
+	}
+}
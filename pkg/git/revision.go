@@ -0,0 +1,217 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cinello/git-dch/internal/errkind"
+
+	"github.com/cinello/go-debian/version"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+var (
+	regExFullHash       = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+	regExUpstream       = regexp.MustCompile(`(?i)^(.*)@\{(upstream|u)\}$`)
+	regExRevisionSuffix = regexp.MustCompile(`^(?:~(\d*)|\^\{(commit)?\}|\^(\d*))`)
+)
+
+// isFullHash returns true if the value is a full commit hash
+func isFullHash(value string) bool {
+
+	return regExFullHash.MatchString(value)
+}
+
+// SplitRange splits a revision range like A..B in its two revisions, an
+// empty revision is HEAD. The last value is false if the value is not a range.
+func SplitRange(value string) (from, to string, ok bool) {
+
+	i := strings.Index(value, "..")
+	if i < 0 {
+		return "", "", false
+	}
+
+	from, to = value[:i], value[i+2:]
+	if from == "" {
+		from = "HEAD"
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+
+	return from, to, true
+}
+
+// ResolveRevision returns the hash of the commit selected by a revision, with
+// the syntax described by gitrevisions(7): a full or abbreviated hash, HEAD or
+// @, a tag, a branch or a reference name, [branch]@{upstream} (or @{u}),
+// followed by any number of ~N, ^N and ^{commit} suffixes.
+func (gr *Repository) ResolveRevision(revision string) (hash string, err error) {
+
+	base := revision
+	if i := strings.IndexAny(revision, "~^"); i >= 0 {
+		base = revision[:i]
+	}
+
+	if hash, err = gr.resolveRevisionBase(base); err != nil {
+		return "", fmt.Errorf(textCannotResolveRevision, revision, err)
+	}
+
+	var c *object.Commit
+	if c, err = gr.repository.CommitObject(plumbing.NewHash(hash)); err != nil {
+		return "", errkind.New(ErrCommitNotFound, textCannotResolveRevision, revision, err)
+	}
+
+	suffix := revision[len(base):]
+	for suffix != "" {
+		values := regExRevisionSuffix.FindStringSubmatch(suffix)
+		if values == nil {
+			return "", fmt.Errorf(textCannotResolveRevision, revision, errkind.New(ErrInvalidRevision, textInvalidRevisionSuffix, suffix))
+		}
+		suffix = suffix[len(values[0]):]
+
+		switch {
+		case strings.HasPrefix(values[0], "~"):
+			// the n-th generation ancestor, following the first parents
+			n := 1
+			if values[1] != "" {
+				n, _ = strconv.Atoi(values[1])
+			}
+			for ; n > 0; n-- {
+				if c, err = gr.parentCommit(c, 1); err != nil {
+					return "", fmt.Errorf(textCannotResolveRevision, revision, err)
+				}
+			}
+		case strings.HasPrefix(values[0], "^{"):
+			// the commits are already peeled
+		default:
+			// the n-th parent, ^0 is the commit itself
+			n := 1
+			if values[3] != "" {
+				n, _ = strconv.Atoi(values[3])
+			}
+			if n > 0 {
+				if c, err = gr.parentCommit(c, n); err != nil {
+					return "", fmt.Errorf(textCannotResolveRevision, revision, err)
+				}
+			}
+		}
+	}
+
+	return c.Hash.String(), nil
+}
+
+// parentCommit returns the n-th parent of a commit, starting from 1
+func (gr *Repository) parentCommit(c *object.Commit, n int) (*object.Commit, error) {
+
+	if n > len(c.ParentHashes) {
+		return nil, errkind.New(ErrInvalidRevision, textCommitHasNoParent, c.Hash.String()[0:7], n)
+	}
+
+	return gr.repository.CommitObject(c.ParentHashes[n-1])
+}
+
+// resolveRevisionBase returns the commit hash of a revision without suffixes
+func (gr *Repository) resolveRevisionBase(base string) (hash string, err error) {
+
+	if base == "" {
+		return "", errkind.New(ErrInvalidRevision, textEmptyRevision)
+	}
+
+	if base == "HEAD" || base == "@" {
+		var head *plumbing.Reference
+		if head, err = gr.repository.Head(); err != nil {
+			return "", fmt.Errorf(textCannotGetHead, err)
+		}
+		return head.Hash().String(), nil
+	}
+
+	if values := regExUpstream.FindStringSubmatch(base); values != nil {
+		var upstream string
+		if upstream, err = gr.upstreamReference(values[1]); err != nil {
+			return
+		}
+		base = upstream
+	}
+
+	if hash = gr.CommitAtTagObject(version.Version{Version: base}); hash != "" {
+		return
+	}
+	if hash = gr.CommitAtReference(base); hash != "" {
+		return gr.peelTag(hash)
+	}
+	if isFullHash(base) {
+		return strings.ToLower(base), nil
+	}
+	if isAbbreviatedHash(base) {
+		return gr.ResolveHash(base)
+	}
+
+	return "", errkind.New(ErrInvalidRevision, textUnknownRevision, base)
+}
+
+// peelTag returns the commit pointed by an annotated tag object, or the hash
+// itself if it is not a tag object
+func (gr *Repository) peelTag(hash string) (string, error) {
+
+	tag, err := gr.repository.TagObject(plumbing.NewHash(hash))
+	if err != nil {
+		return hash, nil
+	}
+
+	c, err := tag.Commit()
+	if err != nil {
+		return "", err
+	}
+
+	return c.Hash.String(), nil
+}
+
+// upstreamReference returns the name of the remote branch tracked by a
+// branch, as set by branch.<name>.remote and branch.<name>.merge in the
+// configuration; an empty branch, HEAD or @ is the active one
+func (gr *Repository) upstreamReference(branch string) (reference string, err error) {
+
+	if branch == "" || branch == "HEAD" || branch == "@" {
+		if branch, err = gr.ActiveBranch(); err != nil {
+			return
+		}
+	}
+
+	var remote, merge string
+	if remote, err = gr.ConfigSubsectionValue("branch", branch, "remote"); err != nil {
+		return
+	}
+	if merge, err = gr.ConfigSubsectionValue("branch", branch, "merge"); err != nil {
+		return
+	}
+	if remote == "" || merge == "" {
+		return "", errkind.New(ErrNoUpstream, textNoUpstream, branch)
+	}
+
+	name := strings.TrimPrefix(merge, "refs/heads/")
+	if remote == "." {
+		return "refs/heads/" + name, nil
+	}
+
+	return "refs/remotes/" + remote + "/" + name, nil
+}
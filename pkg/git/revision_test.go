@@ -0,0 +1,212 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestResolveRevision(t *testing.T) {
+
+	path, hashes := newTestRepository(t,
+		testCommit{message: "Initial commit", file: "README", contents: "readme\n"},
+		testCommit{message: "Add feature", file: "feature.go", contents: "package feature\n"},
+		testCommit{message: "Fix feature", file: "feature.go", contents: "package feature // fixed\n"},
+		testCommit{message: "Add other feature", file: "other.go", contents: "package other\n"},
+	)
+	defer os.RemoveAll(path)
+
+	r, err := git.PlainOpen(path)
+	if err != nil {
+		t.Fatalf("cannot open repository: %s", err)
+	}
+	tagger := &object.Signature{Name: "Release Manager", Email: "release@nomail.org", When: time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)}
+	if _, err = r.CreateTag("v0.1.0", plumbing.NewHash(hashes[1]), &git.CreateTagOptions{Tagger: tagger, Message: "Release"}); err != nil {
+		t.Fatalf("cannot create tag: %s", err)
+	}
+	if err = r.Storer.SetReference(plumbing.NewHashReference("refs/heads/stable", plumbing.NewHash(hashes[0]))); err != nil {
+		t.Fatalf("cannot create branch: %s", err)
+	}
+
+	c, err := r.Config()
+	if err != nil {
+		t.Fatalf("cannot read configuration: %s", err)
+	}
+	c.Branches["master"] = &config.Branch{Name: "master", Remote: ".", Merge: "refs/heads/stable"}
+	if err = r.Storer.SetConfig(c); err != nil {
+		t.Fatalf("cannot set configuration: %s", err)
+	}
+
+	gr, err := NewRepository(path)
+	if err != nil {
+		t.Fatalf("cannot open repository: %s", err)
+	}
+
+	tests := []struct {
+		name      string
+		revision  string
+		want      string
+		wantError error
+	}{
+		{name: `head`, revision: "HEAD", want: hashes[3]},
+		{name: `at`, revision: "@", want: hashes[3]},
+		{name: `tilde`, revision: "HEAD~", want: hashes[2]},
+		{name: `tildeN`, revision: "HEAD~3", want: hashes[0]},
+		{name: `caret`, revision: "HEAD^^", want: hashes[1]},
+		{name: `caretZero`, revision: "HEAD^0", want: hashes[3]},
+		{name: `mixed`, revision: "master~1^1", want: hashes[1]},
+		{name: `abbreviated`, revision: hashes[2][:7] + "~1", want: hashes[1]},
+		{name: `tag`, revision: "v0.1.0", want: hashes[1]},
+		{name: `tagCommit`, revision: "v0.1.0^{commit}", want: hashes[1]},
+		{name: `tagParent`, revision: "refs/tags/v0.1.0^", want: hashes[0]},
+		{name: `upstream`, revision: "@{upstream}", want: hashes[0]},
+		{name: `branchUpstream`, revision: "master@{u}", want: hashes[0]},
+		{name: `headUpstream`, revision: "HEAD@{u}", want: hashes[0]},
+		{name: `atUpstream`, revision: "@@{upstream}", want: hashes[0]},
+		{name: `noUpstream`, revision: "stable@{u}", wantError: ErrNoUpstream},
+		{name: `tooFar`, revision: "HEAD~4", wantError: ErrInvalidRevision},
+		{name: `noParent`, revision: "HEAD^2", wantError: ErrInvalidRevision},
+		{name: `suffix`, revision: "HEAD^{tree}", wantError: ErrInvalidRevision},
+		{name: `unknown`, revision: "wrong/0.0.0", wantError: ErrInvalidRevision},
+		{name: `empty`, revision: "", wantError: ErrInvalidRevision},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gr.ResolveRevision(tt.revision)
+			if !errors.Is(err, tt.wantError) || (err != nil) != (tt.wantError != nil) {
+				t.Fatalf("ResolveRevision() error = %v, wantError %v", err, tt.wantError)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveRevision() = '%v', want '%v'", got, tt.want)
+			}
+		})
+	}
+
+	ranges := []struct {
+		name      string
+		since     string
+		want      []string
+		wantError bool
+	}{
+		{name: `revision`, since: "HEAD~2", want: []string{hashes[3], hashes[2], hashes[1]}},
+		{name: `annotatedTag`, since: "v0.1.0", want: []string{hashes[3], hashes[2], hashes[1]}},
+		{name: `range`, since: "v0.1.0..HEAD~1", want: []string{hashes[2]}},
+		{name: `openRange`, since: hashes[1][:8] + "..", want: []string{hashes[3], hashes[2]}},
+		{name: `symmetric`, since: "v0.1.0...HEAD", wantError: true},
+		{name: `unknown`, since: "HEAD~9", wantError: true},
+	}
+	for _, tt := range ranges {
+		t.Run(tt.name, func(t *testing.T) {
+			commits, err := gr.CommitsToCommit(tt.since, false)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("CommitsToCommit() error = %v, wantError %v", err, tt.wantError)
+			}
+			var got []string
+			for _, c := range commits {
+				got = append(got, c.Hash.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CommitsToCommit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitsToCommitStrict(t *testing.T) {
+
+	path, hashes := newTestRepository(t,
+		testCommit{message: "Initial commit", file: "README", contents: "readme\n"},
+		testCommit{message: "Add feature", file: "feature.go", contents: "package feature\n"},
+		testCommit{message: "Abandoned change", file: "feature.go", contents: "package feature // abandoned\n"},
+	)
+	defer os.RemoveAll(path)
+
+	// the last commit is no more an ancestor of HEAD
+	r, err := git.PlainOpen(path)
+	if err != nil {
+		t.Fatalf("cannot open repository: %s", err)
+	}
+	if err = r.Storer.SetReference(plumbing.NewHashReference("refs/heads/master", plumbing.NewHash(hashes[1]))); err != nil {
+		t.Fatalf("cannot reset branch: %s", err)
+	}
+
+	gr, err := NewRepository(path)
+	if err != nil {
+		t.Fatalf("cannot open repository: %s", err)
+	}
+
+	const missing = "1234567890123456789012345678901234567890"
+	tests := []struct {
+		name            string
+		since           string
+		strict          bool
+		want            []string
+		wantError       bool
+		wantNotAncestor bool
+	}{
+		{name: `ancestor`, since: hashes[0], strict: true, want: []string{hashes[1], hashes[0]}},
+		{name: `upperCase`, since: strings.ToUpper(hashes[1]), strict: true, want: []string{hashes[1]}},
+		{name: `upperCaseLenient`, since: strings.ToUpper(hashes[1]), want: []string{hashes[1]}},
+		{name: `range`, since: hashes[0] + "..", strict: true, want: []string{hashes[1]}},
+		{name: `missing`, since: missing, strict: true, wantError: true},
+		{name: `notAncestor`, since: hashes[2][:7], strict: true, wantError: true, wantNotAncestor: true},
+		{name: `missingLenient`, since: missing, want: []string{hashes[1], hashes[0]}},
+		{name: `notAncestorLenient`, since: hashes[2], want: []string{hashes[1], hashes[0]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commits []*object.Commit
+			if tt.strict {
+				commits, err = gr.CommitsToCommitStrict(tt.since, false)
+			} else {
+				commits, err = gr.CommitsToCommit(tt.since, false)
+			}
+			if (err != nil) != tt.wantError {
+				t.Fatalf("CommitsToCommitStrict() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil {
+				var startErr *StartCommitError
+				if !errors.As(err, &startErr) {
+					t.Fatalf("CommitsToCommitStrict() error = %T, want *StartCommitError", err)
+				}
+				if startErr.NotAncestor != tt.wantNotAncestor {
+					t.Errorf("CommitsToCommitStrict() NotAncestor = %v, want %v", startErr.NotAncestor, tt.wantNotAncestor)
+				}
+				if errors.Is(err, ErrNotAncestor) != tt.wantNotAncestor || errors.Is(err, ErrCommitNotFound) == tt.wantNotAncestor {
+					t.Errorf("CommitsToCommitStrict() error = %v does not match its kind", err)
+				}
+				return
+			}
+			var got []string
+			for _, c := range commits {
+				got = append(got, c.Hash.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CommitsToCommitStrict() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,8 +17,25 @@ package git
 
 const (
 	textCannotOpenWorkDir           = "cannot open working directory: %s"
+	textCannotOpenRepository        = "cannot open git repository %s: %s"
 	textCannotGetConfigurationValue = "cannot get git configuration value: %s"
+	textCannotSetConfigurationValue = "cannot set git configuration value: %s"
 	textCannotGetBranches           = "cannot get branches list: %s"
-	textCannotGetHead               = "cannot get head reference: %s"
+	textCannotGetHead               = "cannot get head reference: %w"
 	textCommitIsNotValidBranch      = "the active commit is not a valid branch"
+	textCannotReadMailmap           = "cannot read mailmap: %s"
+	textInvalidFilterExpression     = "invalid filter expression %s: %s"
+	textInvalidTrailerRule          = "invalid trailer rule %s, expected 'Key: value'"
+	textInvalidHash                 = "%s is not a valid commit hash"
+	textCommitNotFound              = "the commit %s does not exist"
+	textCommitIsNotAncestor         = "the commit %s is not an ancestor of HEAD"
+	textAmbiguousHash               = "the abbreviated hash %s is ambiguous, it matches %s"
+	textCannotResolveRevision       = "cannot resolve revision %s: %w"
+	textEmptyRevision               = "the revision is empty"
+	textUnknownRevision             = "%s is not a commit, a tag or a reference"
+	textInvalidRevisionSuffix       = "unsupported revision suffix %s"
+	textCommitHasNoParent           = "the commit %s has no parent %d"
+	textNoUpstream                  = "the branch %s has no upstream branch"
+	textUnsupportedRange            = "the symmetric difference %s is not supported, use A..B"
+	textNoWorktree                  = "the repository %s has no working tree: %s"
 )
@@ -0,0 +1,194 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cinello/go-debian/version"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+var (
+	regExTagVersion = regexp.MustCompile(`^(?:.*/)?[vV]?(\d[0-9A-Za-z.+~:%_-]*)$`)
+)
+
+// ReleaseTag is a tag whose name contains a version
+type ReleaseTag struct {
+	Name    string
+	Version version.Version
+	Commit  string
+
+	// Tagger is the tagger of an annotated tag, or the author of the
+	// commit of a lightweight one, translated through the mailmap
+	Tagger object.Signature
+}
+
+// tagVersion returns the version in a tag name like "1.2.0", "v1.2.0" or
+// "debian/1:1.2.0-1"; the characters mangled in the Debian tags as
+// described in DEP-14 ("%" for ":" and "_" for "~") are restored
+func tagVersion(name string) (v version.Version, ok bool) {
+
+	values := regExTagVersion.FindStringSubmatch(name)
+	if values == nil {
+		return v, false
+	}
+
+	var err error
+	if v, err = version.Parse(strings.NewReplacer("%", ":", "_", "~").Replace(values[1])); err != nil {
+		return v, false
+	}
+
+	return v, true
+}
+
+// ReleaseTags returns the tags of the repository containing a version, sorted
+// from the newest to the oldest version. When more tags contain the same
+// version, only the first one in alphabetical order is returned.
+func (gr *Repository) ReleaseTags() (tags []ReleaseTag, err error) {
+
+	var refs []*plumbing.Reference
+	i, err := gr.repository.Tags()
+	if err != nil {
+		return
+	}
+	err = i.ForEach(func(ref *plumbing.Reference) error {
+		refs = append(refs, ref)
+		return nil
+	})
+	i.Close()
+	if err != nil {
+		return
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Name().Short() < refs[j].Name().Short()
+	})
+
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		name := ref.Name().Short()
+		v, ok := tagVersion(name)
+		if !ok || seen[v.String()] {
+			continue
+		}
+
+		tag := ReleaseTag{Name: name, Version: v}
+		var c *object.Commit
+		if t, tagErr := gr.repository.TagObject(ref.Hash()); tagErr == nil {
+			if c, err = t.Commit(); err != nil {
+				// annotated tags of objects other than commits are ignored
+				err = nil
+				continue
+			}
+			tag.Tagger = gr.mailmap.Resolve(t.Tagger)
+		} else {
+			if c, err = gr.repository.CommitObject(ref.Hash()); err != nil {
+				err = nil
+				continue
+			}
+			tag.Tagger = gr.CommitAuthor(c)
+		}
+		tag.Commit = c.Hash.String()
+
+		seen[v.String()] = true
+		tags = append(tags, tag)
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return version.Compare(tags[i].Version, tags[j].Version) > 0
+	})
+
+	return
+}
+
+// ancestors returns the set of the commits reachable from a commit, including itself
+func (gr *Repository) ancestors(hash string) (set map[plumbing.Hash]*object.Commit, err error) {
+
+	set = make(map[plumbing.Hash]*object.Commit)
+	if hash == "" {
+		return
+	}
+
+	var i object.CommitIter
+	if i, err = gr.repository.Log(&git.LogOptions{From: plumbing.NewHash(hash)}); err != nil {
+		return
+	}
+	defer i.Close()
+
+	err = i.ForEach(func(c *object.Commit) error {
+		set[c.Hash] = c
+		return nil
+	})
+
+	return
+}
+
+// CommitsBetween returns the commits reachable from the commit to and not
+// from the commit from, sorted from the newest to the oldest. An empty from
+// selects all the history of to.
+func (gr *Repository) CommitsBetween(from, to string, ignoreMerges bool) (commits []*object.Commit, err error) {
+
+	var excluded, included map[plumbing.Hash]*object.Commit
+	if excluded, err = gr.ancestors(from); err != nil {
+		return
+	}
+	if included, err = gr.ancestors(to); err != nil {
+		return
+	}
+
+	for hash, c := range included {
+		if _, ok := excluded[hash]; ok {
+			continue
+		}
+		if ignoreMerges && len(c.ParentHashes) > 1 {
+			continue
+		}
+		if !gr.acceptCommit(c) {
+			continue
+		}
+		commits = append(commits, c)
+	}
+	// commits with the same date are sorted by hash, to get the same order on each run
+	sort.Slice(commits, func(i, j int) bool {
+		return commits[i].Hash.String() < commits[j].Hash.String()
+	})
+	sort.Stable(sortCommitsByDate(commits))
+
+	return gr.netChanges(commits)
+}
+
+// LogBetween returns the log of the commits between two commits, see CommitsBetween
+func (gr *Repository) LogBetween(from, to string, withAuthor, withHash, withStar, full, ignoreMerges bool) (out string, err error) {
+
+	var (
+		list []*object.Commit
+	)
+
+	if list, err = gr.CommitsBetween(from, to, ignoreMerges); err != nil {
+		return
+	}
+
+	for _, c := range list {
+		out += buildLogEntryText(c, gr.CommitAuthor(c), withAuthor, withHash, withStar, full, gr.wrapWidth)
+	}
+
+	return
+}
@@ -0,0 +1,137 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package git
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestTagVersion(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		tag    string
+		want   string
+		wantOk bool
+	}{
+		{name: `plain`, tag: "1.2.0", want: "1.2.0", wantOk: true},
+		{name: `prefix`, tag: "v1.2.0", want: "1.2.0", wantOk: true},
+		{name: `debian`, tag: "debian/2%1.2.0_rc1-1", want: "2:1.2.0~rc1-1", wantOk: true},
+		{name: `name`, tag: "latest", wantOk: false},
+		{name: `words`, tag: "release-1.2", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tagVersion(tt.tag)
+			if ok != tt.wantOk {
+				t.Fatalf("tagVersion() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && got.String() != tt.want {
+				t.Errorf("tagVersion() = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReleaseTags(t *testing.T) {
+
+	path, hashes := newTestRepository(t,
+		testCommit{message: "Initial commit", file: "README", contents: "readme\n"},
+		testCommit{message: "Add feature", file: "feature.go", contents: "package feature\n"},
+		testCommit{message: "Fix feature", file: "feature.go", contents: "package feature // fixed\n"},
+		testCommit{message: "Add other feature", file: "other.go", contents: "package other\n"},
+	)
+	defer os.RemoveAll(path)
+
+	r, err := git.PlainOpen(path)
+	if err != nil {
+		t.Fatalf("cannot open repository: %s", err)
+	}
+	tagger := &object.Signature{Name: "Release Manager", Email: "release@nomail.org", When: time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)}
+	tags := []struct {
+		name      string
+		commit    string
+		annotated bool
+	}{
+		{name: "v0.1.0", commit: hashes[0]},
+		{name: "0.2.0", commit: hashes[2], annotated: true},
+		{name: "v0.2.0", commit: hashes[2]},
+		{name: "latest", commit: hashes[3]},
+	}
+	for _, tag := range tags {
+		var options *git.CreateTagOptions
+		if tag.annotated {
+			options = &git.CreateTagOptions{Tagger: tagger, Message: "Release " + tag.name}
+		}
+		if _, err = r.CreateTag(tag.name, plumbing.NewHash(tag.commit), options); err != nil {
+			t.Fatalf("cannot create tag %s: %s", tag.name, err)
+		}
+	}
+
+	gr, err := NewRepository(path)
+	if err != nil {
+		t.Fatalf("cannot open repository: %s", err)
+	}
+
+	releases, err := gr.ReleaseTags()
+	if err != nil {
+		t.Fatalf("ReleaseTags() error = %v", err)
+	}
+	var got []string
+	for _, tag := range releases {
+		got = append(got, tag.Name+" "+tag.Commit[:7]+" "+tag.Tagger.Name)
+	}
+	want := []string{
+		"0.2.0 " + hashes[2][:7] + " Release Manager",
+		"v0.1.0 " + hashes[0][:7] + " Test Author",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReleaseTags() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want []string
+	}{
+		{name: `first`, from: "", to: hashes[0], want: []string{hashes[0]}},
+		{name: `range`, from: hashes[0], to: hashes[2], want: []string{hashes[2], hashes[1]}},
+		{name: `head`, from: hashes[2], to: hashes[3], want: []string{hashes[3]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commits, err := gr.CommitsBetween(tt.from, tt.to, false)
+			if err != nil {
+				t.Fatalf("CommitsBetween() error = %v", err)
+			}
+			var got []string
+			for _, c := range commits {
+				got = append(got, c.Hash.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CommitsBetween() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log message
+type Level int
+
+const (
+	// LevelDebug reports the details of the decisions
+	LevelDebug Level = iota
+	// LevelInfo reports the decisions: start commits, branches, versions
+	LevelInfo
+	// LevelWarning reports the problems which do not stop the command
+	LevelWarning
+	// LevelError reports the failures
+	LevelError
+)
+
+// String returns the name of the Level
+func (l Level) String() string {
+
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarning:
+		return "warning"
+	}
+
+	return "error"
+}
+
+// Format is the layout of the log lines
+type Format int
+
+const (
+	// TextFormat writes lines like "INFO: message key=value"
+	TextFormat Format = iota
+	// JSONFormat writes a JSON object per line, with the time, level and
+	// msg keys followed by the fields
+	JSONFormat
+)
+
+// ParseFormat returns the Format with the given name: text or json
+func ParseFormat(value string) (Format, error) {
+
+	switch strings.ToLower(value) {
+	case "", "text":
+		return TextFormat, nil
+	case "json":
+		return JSONFormat, nil
+	}
+
+	return TextFormat, fmt.Errorf("the log format %s is not valid", value)
+}
+
+// Field is a key and value pair attached to a log message
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F returns a Field with the given key and value
+func F(key string, value interface{}) Field {
+
+	return Field{Key: key, Value: value}
+}
+
+// Logger writes the messages with at least its level. A nil Logger discards
+// all the messages, so the packages can log without checking it.
+type Logger struct {
+	mu     sync.Mutex
+	w      io.Writer
+	level  Level
+	format Format
+	colors map[Level]string
+	now    func() time.Time
+}
+
+// New returns a Logger writing to w the messages with at least the given level
+func New(w io.Writer, level Level, format Format) *Logger {
+
+	return &Logger{w: w, level: level, format: format, now: time.Now}
+}
+
+// SetColors sets the SGR parameters (e.g. "33" or "1;31") of the level names
+// in the text format, as "\x1b[<parameters>m"; a nil map disables the colors
+func (l *Logger) SetColors(colors map[Level]string) {
+
+	l.colors = colors
+}
+
+// Enabled returns true if the messages with the given level are written
+func (l *Logger) Enabled(level Level) bool {
+
+	return l != nil && level >= l.level
+}
+
+// Debug writes a message with LevelDebug
+func (l *Logger) Debug(msg string, fields ...Field) {
+
+	l.log(LevelDebug, msg, fields)
+}
+
+// Info writes a message with LevelInfo
+func (l *Logger) Info(msg string, fields ...Field) {
+
+	l.log(LevelInfo, msg, fields)
+}
+
+// Warning writes a message with LevelWarning
+func (l *Logger) Warning(msg string, fields ...Field) {
+
+	l.log(LevelWarning, msg, fields)
+}
+
+// Error writes a message with LevelError
+func (l *Logger) Error(msg string, fields ...Field) {
+
+	l.log(LevelError, msg, fields)
+}
+
+func (l *Logger) log(level Level, msg string, fields []Field) {
+
+	if !l.Enabled(level) {
+		return
+	}
+
+	var line []byte
+	if l.format == JSONFormat {
+		line = l.jsonLine(level, msg, fields)
+	} else {
+		line = l.textLine(level, msg, fields)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.w.Write(line)
+}
+
+// textLine returns a line like "WARNING: message key=value", the values with
+// spaces or quotes are quoted
+func (l *Logger) textLine(level Level, msg string, fields []Field) []byte {
+
+	var b bytes.Buffer
+	name := strings.ToUpper(level.String())
+	if color := l.colors[level]; color != "" {
+		name = "\x1b[" + color + "m" + name + "\x1b[0m"
+	}
+	b.WriteString(name)
+	b.WriteString(": ")
+	b.WriteString(msg)
+	for _, f := range fields {
+		value := fmt.Sprint(f.Value)
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		b.WriteString(" " + f.Key + "=" + value)
+	}
+	b.WriteString("\n")
+
+	return b.Bytes()
+}
+
+// jsonLine returns a JSON object with the time, level and msg keys followed
+// by the fields, in their order
+func (l *Logger) jsonLine(level Level, msg string, fields []Field) []byte {
+
+	all := append([]Field{
+		F("time", l.now().Format(time.RFC3339)),
+		F("level", level.String()),
+		F("msg", msg),
+	}, fields...)
+
+	var b bytes.Buffer
+	b.WriteString("{")
+	for i, f := range all {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		key, _ := json.Marshal(f.Key)
+		value := f.Value
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		} else if s, ok := value.(fmt.Stringer); ok {
+			value = s.String()
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			data, _ = json.Marshal(fmt.Sprint(f.Value))
+		}
+		b.Write(key)
+		b.WriteString(":")
+		b.Write(data)
+	}
+	b.WriteString("}\n")
+
+	return b.Bytes()
+}
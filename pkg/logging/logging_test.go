@@ -0,0 +1,90 @@
+// This file is part of git-dch-go.
+//
+// git-dch-go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// git-dch-go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with git-dch-go.  If not, see <http://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLogger(t *testing.T) {
+
+	when := time.Date(2018, 2, 15, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		level  Level
+		format Format
+		colors map[Level]string
+		log    func(l *Logger)
+		want   string
+	}{
+		{
+			name:  `text`,
+			level: LevelInfo,
+			log: func(l *Logger) {
+				l.Info("start commit", F("since", "v1.0"), F("subject", "Add feature"), F("count", 3))
+			},
+			want: "INFO: start commit since=v1.0 subject=\"Add feature\" count=3\n",
+		},
+		{
+			name:  `level`,
+			level: LevelWarning,
+			log: func(l *Logger) {
+				l.Debug("hidden")
+				l.Info("hidden")
+				l.Warning("shown", F("empty", ""))
+			},
+			want: "WARNING: shown empty=\"\"\n",
+		},
+		{
+			name:   `colors`,
+			level:  LevelDebug,
+			colors: map[Level]string{LevelWarning: "33"},
+			log: func(l *Logger) {
+				l.Debug("plain")
+				l.Warning("colored")
+			},
+			want: "DEBUG: plain\n\x1b[33mWARNING\x1b[0m: colored\n",
+		},
+		{
+			name:   `json`,
+			level:  LevelDebug,
+			format: JSONFormat,
+			log: func(l *Logger) {
+				l.Debug("version", F("threshold", LevelInfo), F("error", errors.New("failed")), F("merges", false))
+			},
+			want: `{"time":"2018-02-15T10:30:00Z","level":"debug","msg":"version","threshold":"info","error":"failed","merges":false}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			l := New(&b, tt.level, tt.format)
+			l.SetColors(tt.colors)
+			l.now = func() time.Time { return when }
+			tt.log(l)
+			if got := b.String(); got != tt.want {
+				t.Errorf("Logger output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// a nil Logger discards the messages
+	var l *Logger
+	l.Error("discarded")
+}